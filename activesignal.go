@@ -12,14 +12,20 @@ import (
 func (vs *Kramer4x4) GetActiveSignal(ctx context.Context, port string) (error, structs.ActiveSignal) {
 	rW := true
 	var signal structs.ActiveSignal
-	i, err := ToIndexOne(port)
-	if err != nil || LessThanZero(port) {
-		return fmt.Errorf("Error: %s", err), signal
+
+	dims, err := vs.Dimensions(ctx)
+	if err != nil {
+		return fmt.Errorf("Error: %w", err), signal
+	}
+
+	i, err := parsePort("input", port, dims.Inputs)
+	if err != nil {
+		return fmt.Errorf("Error: %w", err), signal
 	}
 
-	signal, ne := vs.GetActiveSignalByPort(ctx, i, rW)
+	signal, ne := vs.GetActiveSignalByPort(ctx, strconv.Itoa(vs.devicePort(i)), rW)
 	if ne != nil {
-		return fmt.Errorf("Error: %s", err), signal
+		return fmt.Errorf("Error: %w", ne), signal
 	}
 
 	return nil, signal
@@ -57,12 +63,15 @@ func (vs *Kramer4x4) GetActiveSignalByPort(ctx context.Context, port string, rea
 
 	signalResponse, err := vs.hardwareCommand(ctx, Signal, port)
 	if err != nil {
-		return signal, fmt.Errorf("failed to get the signal for %s on %s", port, vs.Address)
+		return signal, fmt.Errorf("failed to get the signal for %s on %s: %w", port, vs.Address, err)
 	}
 
-	signalStatus := strings.Split(signalResponse, ",")[1]
+	parts := strings.Split(signalResponse, ",")
+	if len(parts) != 2 {
+		return signal, fmt.Errorf("unexpected response for the signal for %s on %s: %s", port, vs.Address, signalResponse)
+	}
 
-	if signalStatus == "1" {
+	if parts[1] == "1" {
 		signal.Active = true
 	}
 
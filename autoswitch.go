@@ -0,0 +1,208 @@
+package kramer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// SwitchMode is how an output picks its input (#AV-SW-MODE)
+type SwitchMode int
+
+const (
+	// SwitchManual only changes the input when told to
+	SwitchManual SwitchMode = iota
+	// SwitchPriority switches to the first input in the output's priority list that has a signal
+	SwitchPriority
+	// SwitchLastConnected switches to whichever input most recently got a signal
+	SwitchLastConnected
+)
+
+func (m SwitchMode) String() string {
+	switch m {
+	case SwitchManual:
+		return "manual"
+	case SwitchPriority:
+		return "priority"
+	case SwitchLastConnected:
+		return "last connected"
+	default:
+		return "unknown"
+	}
+}
+
+// _videoLayer is the signal layer auto switching is set on
+const _videoLayer = "1"
+
+// SetSwitchMode sets how output picks its input
+func (vs *Kramer4x4) SetSwitchMode(ctx context.Context, output string, mode SwitchMode) error {
+	return vs.setSwitchMode(ctx, output, mode)
+}
+
+// SwitchModes returns how each of outputs picks its input
+func (vs *Kramer4x4) SwitchModes(ctx context.Context, outputs []string) (map[string]SwitchMode, error) {
+	return vs.switchModes(ctx, outputs)
+}
+
+// SetInputPriority sets the order output checks inputs for a signal in when its
+// switch mode is SwitchPriority, highest priority first
+func (vs *Kramer4x4) SetInputPriority(ctx context.Context, output string, inputs []string) error {
+	return vs.setInputPriority(ctx, output, inputs)
+}
+
+// InputPriority returns the inputs in the order output checks them, highest priority first
+func (vs *Kramer4x4) InputPriority(ctx context.Context, output string) ([]string, error) {
+	return vs.inputPriority(ctx, output)
+}
+
+// SetSwitchMode sets how output picks its input
+func (vsdsp *KramerVP558) SetSwitchMode(ctx context.Context, output string, mode SwitchMode) error {
+	return vsdsp.setSwitchMode(ctx, output, mode)
+}
+
+// SwitchModes returns how each of outputs picks its input
+func (vsdsp *KramerVP558) SwitchModes(ctx context.Context, outputs []string) (map[string]SwitchMode, error) {
+	return vsdsp.switchModes(ctx, outputs)
+}
+
+// SetInputPriority sets the order output checks inputs for a signal in when its
+// switch mode is SwitchPriority, highest priority first
+func (vsdsp *KramerVP558) SetInputPriority(ctx context.Context, output string, inputs []string) error {
+	return vsdsp.setInputPriority(ctx, output, inputs)
+}
+
+// InputPriority returns the inputs in the order output checks them, highest priority first
+func (vsdsp *KramerVP558) InputPriority(ctx context.Context, output string) ([]string, error) {
+	return vsdsp.inputPriority(ctx, output)
+}
+
+func (d *Device) setSwitchMode(ctx context.Context, output string, mode SwitchMode) error {
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return err
+	}
+
+	o, err := parsePort("output", output, dims.Outputs)
+	if err != nil {
+		return err
+	}
+
+	d.Log.Debugf("Setting switch mode on output %v to %v on %v", output, mode, d.Address)
+
+	cmd := NewCommand("AV-SW-MODE", _videoLayer, strconv.Itoa(d.devicePort(o)), strconv.Itoa(int(mode)))
+	if _, err := d.Do(ctx, cmd); err != nil {
+		return fmt.Errorf("unable to set switch mode: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Device) switchModes(ctx context.Context, outputs []string) (map[string]SwitchMode, error) {
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var cmds []Command
+	for _, output := range outputs {
+		o, err := parsePort("output", output, dims.Outputs)
+		if err != nil {
+			return nil, err
+		}
+
+		cmds = append(cmds, NewQuery("AV-SW-MODE", _videoLayer, strconv.Itoa(d.devicePort(o))))
+	}
+
+	replies, err := d.Batch(ctx, cmds...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get switch modes: %w", err)
+	}
+
+	toReturn := make(map[string]SwitchMode, len(outputs))
+	for i, output := range outputs {
+		reply := replies[i]
+
+		// e.g. "~01@AV-SW-MODE 1,1,2"
+		if len(reply.Params) != 3 {
+			return nil, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		mode, err := strconv.Atoi(reply.Params[2])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		toReturn[output] = SwitchMode(mode)
+	}
+
+	return toReturn, nil
+}
+
+func (d *Device) setInputPriority(ctx context.Context, output string, inputs []string) error {
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return err
+	}
+
+	o, err := parsePort("output", output, dims.Outputs)
+	if err != nil {
+		return err
+	}
+
+	if len(inputs) == 0 {
+		return fmt.Errorf("no inputs given")
+	}
+
+	params := []string{_videoLayer, strconv.Itoa(d.devicePort(o))}
+	for _, input := range inputs {
+		i, err := parsePort("input", input, dims.Inputs)
+		if err != nil {
+			return err
+		}
+
+		params = append(params, strconv.Itoa(d.devicePort(i)))
+	}
+
+	d.Log.Debugf("Setting input priority on output %v to %v on %v", output, inputs, d.Address)
+
+	if _, err := d.Do(ctx, NewCommand("PRIORITY", params...)); err != nil {
+		return fmt.Errorf("unable to set input priority: %w", err)
+	}
+
+	return nil
+}
+
+// inputPriority parses the reply to #PRIORITY?, e.g. "~01@PRIORITY 1,1,3,1,2"
+// for output 1 preferring input 3, then 1, then 2
+func (d *Device) inputPriority(ctx context.Context, output string) ([]string, error) {
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := parsePort("output", output, dims.Outputs)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := d.Do(ctx, NewQuery("PRIORITY", _videoLayer, strconv.Itoa(d.devicePort(o))))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get input priority: %w", err)
+	}
+
+	if len(reply.Params) < 2 {
+		return nil, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+	}
+
+	var inputs []string
+	for _, p := range reply.Params[2:] {
+		in, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		inputs = append(inputs, strconv.Itoa(d.localPort(in)))
+	}
+
+	return inputs, nil
+}
@@ -0,0 +1,229 @@
+package kramer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// ErrDeviceUnavailable is returned without contacting the device while its
+// circuit breaker is open
+var ErrDeviceUnavailable = errors.New("device unavailable")
+
+// BreakerState is the state of a circuit breaker
+type BreakerState int
+
+const (
+	// BreakerClosed lets every command through
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails every command with ErrDeviceUnavailable
+	BreakerOpen
+	// BreakerHalfOpen lets a single command through to probe the device
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+const (
+	_defaultBreakerThreshold     = 5
+	_defaultBreakerProbeInterval = 30 * time.Second
+	_breakerProbeTimeout         = 10 * time.Second
+)
+
+// Breaker fails commands fast once a device stops answering, instead of
+// waiting out the dial and read timeouts on every command. It opens after
+// Threshold consecutive failures (timeouts, dropped or refused connections).
+// Every ProbeInterval while it is open, the device is probed in the background
+// (or by the next command, if one comes first); the breaker closes once the
+// device answers, and stays open if it doesn't.
+//
+// A Breaker must not be shared between devices.
+type Breaker struct {
+	// Threshold defaults to 5
+	Threshold int
+
+	// ProbeInterval defaults to 30 seconds
+	ProbeInterval time.Duration
+
+	// OnStateChange is called whenever the state of the breaker changes.
+	// It may be called from a background probe.
+	OnStateChange func(from, to BreakerState)
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+
+	// probe checks whether the device is answering, and timer runs it
+	// once the breaker has been open for ProbeInterval
+	probe func(ctx context.Context) error
+	timer *time.Timer
+}
+
+// State returns the current state of the breaker
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// setProbe sets how the breaker checks whether the device is answering while it's open
+func (b *Breaker) setProbe(probe func(ctx context.Context) error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probe = probe
+}
+
+// allow returns ErrDeviceUnavailable if a command shouldn't be sent to the device
+func (b *Breaker) allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+
+	from := b.state
+	switch {
+	case b.state == BreakerClosed:
+	case b.probing:
+		b.mu.Unlock()
+		return ErrDeviceUnavailable
+	case b.state == BreakerOpen && time.Since(b.openedAt) < b.probeInterval():
+		b.mu.Unlock()
+		return ErrDeviceUnavailable
+	default:
+		b.state = BreakerHalfOpen
+		b.probing = true
+	}
+
+	to := b.state
+	b.mu.Unlock()
+
+	b.changed(from, to)
+	return nil
+}
+
+// record updates the breaker with the result of a command
+func (b *Breaker) record(err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+
+	from := b.state
+	b.probing = false
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		// the caller gave up, so nothing was learned about the device
+	case err != nil && unavailable(err):
+		b.failures++
+
+		if b.state == BreakerHalfOpen || b.failures >= b.threshold() {
+			b.state = BreakerOpen
+			b.openedAt = time.Now()
+			b.scheduleProbe()
+		}
+	default:
+		// the device answered, even if it was with an error
+		b.state = BreakerClosed
+		b.failures = 0
+	}
+
+	to := b.state
+	b.mu.Unlock()
+
+	b.changed(from, to)
+}
+
+// scheduleProbe probes the device once ProbeInterval has passed. b.mu must be held.
+func (b *Breaker) scheduleProbe() {
+	if b.probe == nil {
+		return
+	}
+
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+
+	b.timer = time.AfterFunc(b.probeInterval(), b.runProbe)
+}
+
+// runProbe probes the device if the breaker is still open and no command is already probing it
+func (b *Breaker) runProbe() {
+	b.mu.Lock()
+	probe := b.probe
+	open := b.state == BreakerOpen && !b.probing
+	b.mu.Unlock()
+
+	if !open {
+		return
+	}
+
+	if err := b.allow(); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), _breakerProbeTimeout)
+	defer cancel()
+
+	b.record(probe(ctx))
+}
+
+func (b *Breaker) changed(from, to BreakerState) {
+	if from != to && b.OnStateChange != nil {
+		b.OnStateChange(from, to)
+	}
+}
+
+func (b *Breaker) threshold() int {
+	if b.Threshold > 0 {
+		return b.Threshold
+	}
+
+	return _defaultBreakerThreshold
+}
+
+func (b *Breaker) probeInterval() time.Duration {
+	if b.ProbeInterval > 0 {
+		return b.ProbeInterval
+	}
+
+	return _defaultBreakerProbeInterval
+}
+
+// unavailable reports whether err means that the device didn't answer
+func unavailable(err error) bool {
+	var serr *syncError
+
+	switch {
+	case errors.As(err, &serr):
+		// the device answered, even if it didn't make sense
+		return false
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	}
+
+	var nerr net.Error
+	return errors.As(err, &nerr)
+}
@@ -0,0 +1,171 @@
+package kramer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// transitions returns a breaker that sends each state change on the returned channel
+func transitions(threshold int) (*Breaker, chan string) {
+	ch := make(chan string, 16)
+
+	return &Breaker{
+		Threshold:     threshold,
+		ProbeInterval: 20 * time.Millisecond,
+		OnStateChange: func(from, to BreakerState) {
+			ch <- fmt.Sprintf("%s->%s", from, to)
+		},
+	}, ch
+}
+
+func expectTransitions(t *testing.T, ch chan string, want ...string) {
+	t.Helper()
+
+	for _, w := range want {
+		select {
+		case got := <-ch:
+			if got != w {
+				t.Fatalf("got transition %s, want %s", got, w)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for transition %s", w)
+		}
+	}
+}
+
+func TestBreakerProbe(t *testing.T) {
+	b, ch := transitions(2)
+
+	var probes int32
+	b.setProbe(func(context.Context) error {
+		atomic.AddInt32(&probes, 1)
+		return nil
+	})
+
+	b.record(context.DeadlineExceeded)
+	if b.State() != BreakerClosed {
+		t.Fatalf("breaker opened before the threshold")
+	}
+
+	b.record(context.DeadlineExceeded)
+	expectTransitions(t, ch, "closed->open")
+
+	if err := b.allow(); !errors.Is(err, ErrDeviceUnavailable) {
+		t.Fatalf("open breaker let a command through: %v", err)
+	}
+
+	// without any commands, the background probe closes the breaker
+	expectTransitions(t, ch, "open->half-open", "half-open->closed")
+
+	if n := atomic.LoadInt32(&probes); n != 1 {
+		t.Errorf("device was probed %d times, want 1", n)
+	}
+
+	if err := b.allow(); err != nil {
+		t.Errorf("closed breaker didn't let a command through: %v", err)
+	}
+}
+
+func TestBreakerFailedProbe(t *testing.T) {
+	b, ch := transitions(1)
+
+	var probes int32
+	b.setProbe(func(context.Context) error {
+		if atomic.AddInt32(&probes, 1) == 1 {
+			return &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+		}
+
+		return nil
+	})
+
+	b.record(context.DeadlineExceeded)
+	expectTransitions(t, ch,
+		"closed->open",
+		"open->half-open", "half-open->open", // the first probe fails
+		"open->half-open", "half-open->closed",
+	)
+}
+
+func TestBreakerCommandProbe(t *testing.T) {
+	b, ch := transitions(1)
+
+	b.record(timeoutError{})
+	expectTransitions(t, ch, "closed->open")
+
+	time.Sleep(b.ProbeInterval)
+
+	// with no background probe, the next command probes the device, and
+	// everything else fails fast until it's done
+	if err := b.allow(); err != nil {
+		t.Fatalf("breaker didn't let the probe through: %v", err)
+	}
+	expectTransitions(t, ch, "open->half-open")
+
+	if err := b.allow(); !errors.Is(err, ErrDeviceUnavailable) {
+		t.Fatalf("half-open breaker let a second command through: %v", err)
+	}
+
+	b.record(timeoutError{})
+	expectTransitions(t, ch, "half-open->open")
+
+	time.Sleep(b.ProbeInterval)
+
+	if err := b.allow(); err != nil {
+		t.Fatalf("breaker didn't let the probe through: %v", err)
+	}
+
+	// an error from the device means it's answering
+	b.record(ErrBusy)
+	expectTransitions(t, ch, "open->half-open", "half-open->closed")
+}
+
+// timeoutError is a net.Error like the one returned when a read times out
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestBreakerProbesDevice(t *testing.T) {
+	port, stop := fakeModelDevice(t, "VS-44")
+	defer stop()
+
+	var down int32 = 1
+	transport := dialFunc(func(ctx context.Context) (net.Conn, error) {
+		if atomic.LoadInt32(&down) == 1 {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+		}
+
+		return TCPTransport{Address: "127.0.0.1", Port: port}.Dial(ctx)
+	})
+
+	b, ch := transitions(1)
+	d := NewDevice("127.0.0.1", ProfileVS44,
+		WithTransport(transport),
+		WithRetryPolicy(RetryPolicy{Attempts: 1}),
+		WithBreaker(b),
+	)
+
+	if _, err := d.Do(context.Background(), NewQuery(Model)); err == nil {
+		t.Fatalf("expected an error while the device is down")
+	}
+
+	expectTransitions(t, ch, "closed->open", "open->half-open", "half-open->open")
+
+	atomic.StoreInt32(&down, 0)
+	expectTransitions(t, ch, "open->half-open", "half-open->closed")
+
+	reply, err := d.Do(context.Background(), NewQuery(Model))
+	if err != nil {
+		t.Fatalf("device is back up, but: %s", err)
+	}
+
+	if reply.Value() != "VS-44" {
+		t.Errorf("got reply %s", reply)
+	}
+}
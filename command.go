@@ -0,0 +1,220 @@
+package kramer
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Command is a single Protocol 3000 command, e.g. "#VID 1>2" or "#2@MODEL?".
+// Use NewCommand and NewQuery to build one, and Do to send it.
+type Command struct {
+	// Machine is the destination machine number, for daisy-chained devices.
+	// If it is 0 the driver's (or context's, see WithMachine) machine number is used.
+	Machine int
+
+	// Name is the command name without the leading '#' or trailing '?', e.g. "VID"
+	Name string
+
+	// Query is set for queries (e.g. "#VID? 1")
+	Query bool
+
+	// Params are the command's parameters, which are sent separated by commas
+	Params []string
+
+	// replyParams is how many parameters the reply to a "*" query must have, so
+	// that a notification (e.g. "~01@VID 3>1") isn't taken as the reply to "#VID? *"
+	replyParams int
+}
+
+// NewCommand returns a command that sets something on the device, e.g. NewCommand("VID", "1>2")
+func NewCommand(name string, params ...string) Command {
+	return Command{
+		Name:   name,
+		Params: params,
+	}
+}
+
+// NewQuery returns a command that queries the device, e.g. NewQuery("VID", "2")
+func NewQuery(name string, params ...string) Command {
+	return Command{
+		Name:   name,
+		Query:  true,
+		Params: params,
+	}
+}
+
+// ToMachine returns a copy of c addressed to machine number n
+func (c Command) ToMachine(n int) Command {
+	c.Machine = n
+	return c
+}
+
+// Validate returns an error if c can't be encoded as a Protocol 3000 command
+func (c Command) Validate() error {
+	if !validCommandName(c.Name) {
+		return fmt.Errorf("invalid command name %q", c.Name)
+	}
+
+	if c.Machine < 0 {
+		return fmt.Errorf("invalid machine number %d", c.Machine)
+	}
+
+	for _, p := range c.Params {
+		if strings.ContainsAny(p, ",|\r\n") {
+			return fmt.Errorf("invalid parameter %q for %s", p, c.Name)
+		}
+	}
+
+	return nil
+}
+
+// nonIdempotent are commands that don't have the same effect when sent twice
+var nonIdempotent = map[string]bool{
+	"RESET":   true,
+	"FACTORY": true,
+	"UPGRADE": true,
+}
+
+// Idempotent reports whether sending c twice has the same effect as sending
+// it once, i.e. whether it can safely be retried. Queries are idempotent;
+// commands like RESET and relative changes ("AUD-LVL 1,++") are not.
+func (c Command) Idempotent() bool {
+	if c.Query {
+		return true
+	}
+
+	if nonIdempotent[strings.ToUpper(c.Name)] {
+		return false
+	}
+
+	for _, p := range c.Params {
+		if strings.HasPrefix(p, "++") || strings.HasPrefix(p, "--") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Bytes returns the command as it is sent on the wire, e.g. "#VID? 1\r" or "#2@VID? 1\r"
+func (c Command) Bytes() []byte {
+	return encodeBatch([]Command{c})
+}
+
+func (c Command) String() string {
+	return "#" + c.body()
+}
+
+// body returns the command without the leading '#' or trailing carriage return
+func (c Command) body() string {
+	var sb strings.Builder
+
+	if c.Machine > 0 {
+		sb.WriteString(strconv.Itoa(c.Machine) + "@")
+	}
+
+	sb.WriteString(c.Name)
+
+	if c.Query {
+		sb.WriteByte('?')
+	}
+
+	if len(c.Params) > 0 {
+		sb.WriteString(" " + strings.Join(c.Params, ","))
+	}
+
+	return sb.String()
+}
+
+// encodeBatch joins cmds into a single frame, e.g. "#MODEL?|SN?\r"
+func encodeBatch(cmds []Command) []byte {
+	var b bytes.Buffer
+	b.WriteByte('#')
+
+	for i, c := range cmds {
+		if i > 0 {
+			b.WriteByte('|')
+		}
+
+		b.WriteString(c.body())
+	}
+
+	b.WriteByte(CARRIAGE_RETURN)
+	return b.Bytes()
+}
+
+// _maxBatchLength is the longest frame that batches are packed into
+const _maxBatchLength = 256
+
+// splitBatch splits cmds into batches that each fit in a single frame
+func splitBatch(cmds []Command) [][]Command {
+	var batches [][]Command
+	var cur []Command
+	length := 0
+
+	for _, c := range cmds {
+		l := len(c.body()) + 1
+		if len(cur) > 0 && length+l > _maxBatchLength {
+			batches = append(batches, cur)
+			cur = nil
+			length = 0
+		}
+
+		cur = append(cur, c)
+		length += l
+	}
+
+	if len(cur) > 0 {
+		batches = append(batches, cur)
+	}
+
+	return batches
+}
+
+// isReply reports whether r is the device's reply to c, as opposed to an
+// unsolicited notification. Replies to sets always carry OK or ERR, while
+// notifications never do; replies to queries carry no status, so they are
+// matched against the query's parameters.
+func (c Command) isReply(r Reply) bool {
+	if c.Machine > 0 && r.MachineID != c.Machine {
+		return false
+	}
+
+	if r.Status == StatusErr {
+		return len(r.Command) == 0 || strings.EqualFold(r.Command, c.Name)
+	}
+
+	if !strings.EqualFold(r.Command, c.Name) {
+		return false
+	}
+
+	if !c.Query {
+		return r.Status == StatusOK
+	}
+
+	for i, p := range c.Params {
+		if p == "*" {
+			return c.replyParams == 0 || len(r.Params) == c.replyParams
+		}
+
+		if i >= len(r.Params) || !paramMatches(p, r.Params[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// paramMatches compares a query parameter with the matching reply parameter.
+// Routing replies come back as "in>out" for a query of "out".
+func paramMatches(query, reply string) bool {
+	if !strings.Contains(query, ">") {
+		if i := strings.LastIndexByte(reply, '>'); i >= 0 {
+			reply = reply[i+1:]
+		}
+	}
+
+	return strings.EqualFold(query, reply)
+}
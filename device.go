@@ -0,0 +1,436 @@
+package kramer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/byuoitav/connpool"
+)
+
+// Device is a Protocol 3000 device. Kramer4x4, KramerVP558 and KramerAFM20DSP
+// wrap a Device with model specific methods; any other Protocol 3000 device
+// can be controlled with a Device from NewDevice.
+type Device struct {
+	Address string
+	Log     Logger
+
+	profile   Profile
+	machine   int
+	exclusive bool
+	retry     RetryPolicy
+	breaker   *Breaker
+	limiter   *limiter
+	pool      *connpool.Pool
+	events    *notifier
+
+	mu     sync.Mutex
+	banner string
+	dims   *Dimensions
+}
+
+var (
+	_defaultTTL   = 30 * time.Second
+	_defaultDelay = 500 * time.Millisecond
+)
+
+type options struct {
+	ttl       time.Duration
+	delay     time.Duration
+	handshake time.Duration
+	retry     RetryPolicy
+	breaker   *Breaker
+	rate      float64
+	burst     int
+	spacing   time.Duration
+	logger    Logger
+	machine   int
+	transport Transport
+	username  string
+	password  string
+}
+
+// Option configures a Device. The same options work with every driver,
+// e.g. NewVideoSwitcher(addr, WithMachineNumber(2), WithRateLimit(10, 5)).
+type Option interface {
+	apply(*options)
+}
+
+type optionFunc func(*options)
+
+func (f optionFunc) apply(o *options) {
+	f(o)
+}
+
+// WithLogger sets the logger used by the device
+func WithLogger(l Logger) Option {
+	return optionFunc(func(o *options) {
+		o.logger = l
+	})
+}
+
+// WithMachineNumber addresses every command to machine number n (i.e. "#n@CMD"),
+// for devices that are daisy-chained behind the device at addr
+func WithMachineNumber(n int) Option {
+	return optionFunc(func(o *options) {
+		o.machine = n
+	})
+}
+
+// WithTransport sets how to connect to the device. Defaults to TCP port 5000 on addr.
+func WithTransport(t Transport) Option {
+	return optionFunc(func(o *options) {
+		o.transport = t
+	})
+}
+
+// WithCredentials logs in (#LOGIN) on every new connection, for devices with security enabled
+func WithCredentials(username, password string) Option {
+	return optionFunc(func(o *options) {
+		o.username = username
+		o.password = password
+	})
+}
+
+// WithHandshakeTimeout sets how long to wait for the device to answer
+// the handshake sent on every new connection. Defaults to 3 seconds.
+func WithHandshakeTimeout(timeout time.Duration) Option {
+	return optionFunc(func(o *options) {
+		o.handshake = timeout
+	})
+}
+
+// WithRetryPolicy sets how commands are retried after transient failures.
+// Defaults to DefaultRetryPolicy.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return optionFunc(func(o *options) {
+		o.retry = p
+	})
+}
+
+// WithBreaker fails commands fast with ErrDeviceUnavailable once the device
+// stops answering. Each device needs its own Breaker.
+func WithBreaker(b *Breaker) Option {
+	return optionFunc(func(o *options) {
+		o.breaker = b
+	})
+}
+
+// WithRateLimit limits commands sent to the device to perSecond, with bursts of up to burst commands
+func WithRateLimit(perSecond float64, burst int) Option {
+	return optionFunc(func(o *options) {
+		o.rate = perSecond
+		o.burst = burst
+	})
+}
+
+// WithCommandSpacing waits at least d after each command finishes before sending the next one
+func WithCommandSpacing(d time.Duration) Option {
+	return optionFunc(func(o *options) {
+		o.spacing = d
+	})
+}
+
+// NewDevice returns a Device at addr that behaves as described by profile
+func NewDevice(addr string, profile Profile, opts ...Option) *Device {
+	options := options{
+		ttl:       _defaultTTL,
+		delay:     _defaultDelay,
+		handshake: _defaultHandshakeTimeout,
+		retry:     DefaultRetryPolicy,
+	}
+
+	for _, o := range opts {
+		o.apply(&options)
+	}
+
+	d := &Device{
+		Address: addr,
+		Log:     options.logger,
+		profile: profile,
+		machine: options.machine,
+		retry:   options.retry,
+		breaker: options.breaker,
+		limiter: newLimiter(options.rate, options.burst, options.spacing),
+		pool: &connpool.Pool{
+			TTL:    options.ttl,
+			Delay:  options.delay,
+			Logger: options.logger,
+		},
+	}
+
+	if d.Log == nil {
+		d.Log = nopLogger{}
+	}
+
+	transport := options.transport
+	if transport == nil {
+		transport = TCPTransport{Address: addr}
+	}
+
+	dial := func(ctx context.Context) (net.Conn, error) {
+		raw, err := transport.Dial(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open connection: %w", err)
+		}
+
+		conn := newBufferedConn(raw)
+
+		banner, err := handshake(ctx, conn, options.handshake)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		if len(banner) > 0 {
+			d.Log.Debugf("%s sent banner: %s", addr, banner)
+
+			d.mu.Lock()
+			d.banner = banner
+			d.mu.Unlock()
+		}
+
+		if len(options.username) > 0 {
+			if err := login(conn, options.username, options.password); err != nil {
+				conn.Close()
+				return nil, err
+			}
+		}
+
+		return conn, nil
+	}
+
+	d.exclusive = isExclusive(transport)
+	d.pool.NewConnection = dial
+	d.events = newNotifier(dial, options.logger, d.exclusive || !profile.Notifies)
+	d.breaker.setProbe(d.probe)
+
+	return d
+}
+
+// Profile returns the profile of the device
+func (d *Device) Profile() Profile {
+	return d.profile
+}
+
+// Banner returns the welcome message the device sent when the last
+// connection to it was opened, if it sent one
+func (d *Device) Banner() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.banner
+}
+
+// setProfile changes the profile of the device. It must be called before
+// anyone subscribes to the device's notifications.
+func (d *Device) setProfile(p Profile) {
+	d.mu.Lock()
+	d.dims = nil
+	d.mu.Unlock()
+
+	d.profile = p
+	d.events.passive = d.exclusive || !p.Notifies
+}
+
+// devicePort converts a zero based port to the device's port number
+func (d *Device) devicePort(port int) int {
+	return port + d.profile.IndexBase
+}
+
+// localPort converts the device's port number to a zero based port
+func (d *Device) localPort(port int) int {
+	return port - d.profile.IndexBase
+}
+
+// SendCommand sends the byte array to the desired address of projector
+//
+// Deprecated: use Do, which encodes the command and parses the reply.
+func (d *Device) SendCommand(ctx context.Context, cmd []byte) ([]byte, error) {
+	var resp []byte
+
+	err := d.pool.Do(ctx, func(conn connpool.Conn) error {
+		_ = conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+
+		readDur := time.Now().Add(3 * time.Second)
+		n, err := conn.Write(cmd)
+		switch {
+		case err != nil:
+			return err
+		case n != len(cmd):
+			return fmt.Errorf("wrote %v/%v bytes of command 0x%x", n, len(cmd), cmd)
+		}
+
+		resp, err = conn.ReadUntil(LINE_FEED, readDur)
+		if err != nil {
+			return fmt.Errorf("unable to read response: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Do sends cmd to the device and returns its reply, skipping over any
+// change notifications that arrive first. If the device rejects the command,
+// the reply is returned along with an *Error.
+func (d *Device) Do(ctx context.Context, cmd Command) (Reply, error) {
+	replies, err := d.Batch(ctx, cmd)
+	if len(replies) == 0 {
+		return Reply{}, err
+	}
+
+	return replies[0], err
+}
+
+// Batch sends cmds to the device in as few frames as possible ("#CMD1|CMD2|...")
+// and returns the replies in the same order as cmds. If the device rejected any of
+// the commands, the first error is returned along with the replies.
+//
+// Transient failures are retried according to the device's RetryPolicy, as
+// long as every command is idempotent. Commands wait their turn behind any
+// commands with a higher priority (see WithPriority).
+//
+// Commands the device's profile doesn't support fail with ErrCommandNotAvailable
+// without being sent.
+func (d *Device) Batch(ctx context.Context, cmds ...Command) ([]Reply, error) {
+	if len(cmds) == 0 {
+		return nil, nil
+	}
+
+	machine := machineNumber(ctx, d.machine)
+
+	addressed := make([]Command, len(cmds))
+	for i := range cmds {
+		if err := cmds[i].Validate(); err != nil {
+			return nil, err
+		}
+
+		if !d.profile.Supports(cmds[i].Name) {
+			return nil, &Error{Code: ErrCommandNotAvailable.Code, Command: cmds[i].Name}
+		}
+
+		addressed[i] = cmds[i]
+		if addressed[i].Machine == 0 {
+			addressed[i].Machine = machine
+		}
+	}
+
+	idempotent := true
+	for _, cmd := range addressed {
+		idempotent = idempotent && cmd.Idempotent()
+	}
+
+	var replies []Reply
+
+	err := d.retry.do(ctx, d.Log, idempotent, func() error {
+		replies = nil
+
+		err := d.transfer(ctx, func(conn connpool.Conn) error {
+			for _, b := range splitBatch(addressed) {
+				r, err := exchange(conn, b, d.events.skipped)
+				if err != nil {
+					return err
+				}
+
+				replies = append(replies, r...)
+			}
+
+			return nil
+		})
+		if err != nil {
+			replies = nil
+			return err
+		}
+
+		// retry if the device was too busy to do any of the commands
+		for _, reply := range replies {
+			if err := reply.Err(); errors.Is(err, ErrBusy) {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil && len(replies) == 0 {
+		return nil, err
+	}
+
+	for _, reply := range replies {
+		if err := reply.Err(); err != nil {
+			return replies, err
+		}
+	}
+
+	return replies, nil
+}
+
+// transfer runs fn with a connection to the device once it is the caller's turn,
+// for exchanges that Batch can't do (e.g. sending binary data).
+func (d *Device) transfer(ctx context.Context, fn func(conn connpool.Conn) error) error {
+	done, err := d.limiter.wait(ctx)
+	if err != nil {
+		return fmt.Errorf("gave up waiting to send command: %w", err)
+	}
+	defer done()
+
+	if err := d.breaker.allow(); err != nil {
+		return fmt.Errorf("%s: %w", d.Address, err)
+	}
+
+	err = d.use(ctx, fn)
+	d.breaker.record(err)
+
+	return err
+}
+
+// use runs fn with a connection from the pool. The connection is closed if fn
+// fails with anything but an *Error from the device, since it may be out of
+// sync with the device. Failures to read or write are returned as a connError,
+// and anything else (e.g. a reply that can't be parsed) as a syncError.
+func (d *Device) use(ctx context.Context, fn func(conn connpool.Conn) error) error {
+	return d.pool.Do(ctx, func(conn connpool.Conn) error {
+		var perr *Error
+		var nerr net.Error
+
+		err := fn(conn)
+		switch {
+		case err == nil, errors.As(err, &perr):
+			return err
+		case errors.As(err, &nerr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			return &connError{err}
+		default:
+			return &syncError{err}
+		}
+	})
+}
+
+// probe sends an empty command ("#") to check whether the device is answering,
+// for the breaker to probe the device while it's open
+func (d *Device) probe(ctx context.Context) error {
+	done, err := d.limiter.wait(WithPriority(ctx, PriorityBackground))
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	return d.use(ctx, func(conn connpool.Conn) error {
+		_, err := exchange(conn, []Command{NewCommand("")}, d.events.skipped)
+		return err
+	})
+}
+
+// Subscribe returns a channel of change notifications (route, signal, volume, mute...)
+// sent by the device. If no kinds are given, every notification is sent.
+// The channel is closed once ctx is done.
+func (d *Device) Subscribe(ctx context.Context, kinds ...NotificationKind) <-chan Notification {
+	return d.events.subscribe(ctx, kinds)
+}
@@ -0,0 +1,104 @@
+package kramer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dimensions are the number of video inputs and outputs of a switcher
+type Dimensions struct {
+	Inputs  int
+	Outputs int
+}
+
+// Dimensions returns the number of inputs and outputs of the device. They are
+// read with #INFO-IO? if the device supports it, or taken from its profile if not.
+// Other errors (e.g. ErrBusy) are returned, and INFO-IO is asked again next time.
+func (d *Device) Dimensions(ctx context.Context) (Dimensions, error) {
+	d.mu.Lock()
+	dims := d.dims
+	d.mu.Unlock()
+
+	if dims != nil {
+		return *dims, nil
+	}
+
+	reply, err := d.Do(ctx, NewQuery("INFO-IO"))
+
+	switch {
+	case err == nil:
+		dims, err = parseInfoIO(reply)
+		if err != nil {
+			return Dimensions{}, err
+		}
+	case unsupported(err):
+		// the device doesn't support #INFO-IO?
+		if d.profile.Inputs == 0 && d.profile.Outputs == 0 {
+			return Dimensions{}, fmt.Errorf("unable to get dimensions of %s: %w", d.Address, err)
+		}
+
+		dims = &Dimensions{Inputs: d.profile.Inputs, Outputs: d.profile.Outputs}
+	default:
+		return Dimensions{}, fmt.Errorf("unable to get dimensions of %s: %w", d.Address, err)
+	}
+
+	d.mu.Lock()
+	d.dims = dims
+	d.mu.Unlock()
+
+	return *dims, nil
+}
+
+// unsupported reports whether err means that the device doesn't support a
+// command, as opposed to being unable to run it right now (e.g. ErrBusy)
+func unsupported(err error) bool {
+	return errors.Is(err, ErrCommandNotAvailable) || errors.Is(err, ErrSyntax) || errors.Is(err, ErrFeatureNotSupported)
+}
+
+// parseInfoIO parses the reply to #INFO-IO?, e.g. "~01@INFO-IO IN 4,OUT 4"
+func parseInfoIO(reply Reply) (*Dimensions, error) {
+	var dims Dimensions
+	var in, out bool
+
+	for _, p := range reply.Params {
+		fields := strings.Fields(p)
+		if len(fields) != 2 {
+			continue
+		}
+
+		n, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid port count in %s: %w", reply, err)
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "IN":
+			dims.Inputs, in = n, true
+		case "OUT":
+			dims.Outputs, out = n, true
+		}
+	}
+
+	if !in || !out {
+		return nil, fmt.Errorf("unexpected response to INFO-IO: %s", reply)
+	}
+
+	return &dims, nil
+}
+
+// parsePort parses a zero based port number, and checks that it is less than count
+func parsePort(kind, port string, count int) (int, error) {
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", kind, port, err)
+	}
+
+	if n < 0 || n >= count {
+		return 0, fmt.Errorf("%s %d is not between 0 and %d: %w", kind, n, count-1, ErrParameterOutOfRange)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,123 @@
+package kramer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParseInfoIO(t *testing.T) {
+	tests := []struct {
+		line string
+		dims *Dimensions
+	}{
+		{"~01@INFO-IO IN 4,OUT 4", &Dimensions{Inputs: 4, Outputs: 4}},
+		{"~01@INFO-IO IN 11,OUT 2", &Dimensions{Inputs: 11, Outputs: 2}},
+		{"~01@INFO-IO OUT 2,IN 8", &Dimensions{Inputs: 8, Outputs: 2}},
+		{"~01@INFO-IO in 6,out 1", &Dimensions{Inputs: 6, Outputs: 1}},
+		{"~01@INFO-IO IN 4,OUT 4,PRESET 8", &Dimensions{Inputs: 4, Outputs: 4}},
+		{"~01@INFO-IO IN 4", nil},
+		{"~01@INFO-IO IN x,OUT 4", nil},
+		{"~01@INFO-IO", nil},
+	}
+
+	for _, tt := range tests {
+		reply, err := ParseReply([]byte(tt.line))
+		if err != nil {
+			t.Fatalf("ParseReply(%q): %s", tt.line, err)
+		}
+
+		dims, err := parseInfoIO(reply)
+		switch {
+		case tt.dims == nil && err == nil:
+			t.Errorf("parseInfoIO(%q): expected an error, got %+v", tt.line, dims)
+		case tt.dims != nil && err != nil:
+			t.Errorf("parseInfoIO(%q): %s", tt.line, err)
+		case tt.dims != nil && !reflect.DeepEqual(dims, tt.dims):
+			t.Errorf("parseInfoIO(%q) = %+v, want %+v", tt.line, dims, tt.dims)
+		}
+	}
+}
+
+func TestDimensions(t *testing.T) {
+	tests := []struct {
+		name    string
+		replies []string
+		dims    []Dimensions
+		err     []error
+		queries int32
+	}{
+		{
+			name:    "INFO-IO",
+			replies: []string{"~01@INFO-IO IN 6,OUT 2"},
+			dims:    []Dimensions{{6, 2}, {6, 2}},
+			queries: 1,
+		},
+		{
+			name:    "not supported",
+			replies: []string{"~01@INFO-IO ERR 002"},
+			dims:    []Dimensions{{4, 4}, {4, 4}},
+			queries: 1,
+		},
+		{
+			name:    "busy",
+			replies: []string{"~01@INFO-IO ERR 006", "~01@INFO-IO IN 6,OUT 2"},
+			dims:    []Dimensions{{}, {6, 2}},
+			err:     []error{ErrBusy, nil},
+			queries: 2,
+		},
+		{
+			name:    "unauthorized",
+			replies: []string{"~01@INFO-IO ERR 004", "~01@INFO-IO IN 6,OUT 2"},
+			dims:    []Dimensions{{}, {6, 2}},
+			err:     []error{ErrUnauthorized, nil},
+			queries: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		var queries int32
+		port, stop := fakeDevice(t, func(cmd string) string {
+			if cmd != "INFO-IO?" {
+				return ""
+			}
+
+			n := atomic.AddInt32(&queries, 1)
+			if int(n) > len(tt.replies) {
+				return tt.replies[len(tt.replies)-1]
+			}
+
+			return tt.replies[n-1]
+		})
+
+		d := NewDevice("127.0.0.1", ProfileVS44,
+			WithTransport(TCPTransport{Address: "127.0.0.1", Port: port}),
+			WithRetryPolicy(RetryPolicy{Attempts: 1}),
+		)
+
+		for i, want := range tt.dims {
+			var wantErr error
+			if i < len(tt.err) {
+				wantErr = tt.err[i]
+			}
+
+			dims, err := d.Dimensions(context.Background())
+			switch {
+			case wantErr == nil && err != nil:
+				t.Errorf("%s: call %d: %s", tt.name, i+1, err)
+			case wantErr != nil && !errors.Is(err, wantErr):
+				t.Errorf("%s: call %d: got error %v, want %v", tt.name, i+1, err, wantErr)
+			case dims != want:
+				t.Errorf("%s: call %d: got %+v, want %+v", tt.name, i+1, dims, want)
+			}
+		}
+
+		if n := atomic.LoadInt32(&queries); n != tt.queries {
+			t.Errorf("%s: INFO-IO was sent %d times, want %d", tt.name, n, tt.queries)
+		}
+
+		stop()
+	}
+}
@@ -0,0 +1,459 @@
+package kramer
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/byuoitav/connpool"
+)
+
+// EDIDSourceType is where an EDID comes from
+type EDIDSourceType int
+
+const (
+	EDIDFromInput EDIDSourceType = iota
+	EDIDFromOutput
+	EDIDDefault
+	EDIDCustom
+)
+
+func (t EDIDSourceType) String() string {
+	switch t {
+	case EDIDFromInput:
+		return "input"
+	case EDIDFromOutput:
+		return "output"
+	case EDIDDefault:
+		return "default"
+	case EDIDCustom:
+		return "custom"
+	default:
+		return "unknown"
+	}
+}
+
+// EDIDSource is an EDID on the device. Port is the (zero based) input or output
+// for EDIDFromInput and EDIDFromOutput, and the custom EDID's index for EDIDCustom.
+type EDIDSource struct {
+	Type EDIDSourceType
+	Port int
+}
+
+const (
+	_edidPacketSize = 64
+	_edidTimeout    = 5 * time.Second
+)
+
+// CopyEDID copies the EDID from src to each of inputs (#CPEDID)
+func (vs *Kramer4x4) CopyEDID(ctx context.Context, src EDIDSource, inputs ...string) error {
+	return vs.copyEDID(ctx, src, inputs)
+}
+
+// LockEDID locks (or unlocks) the EDID of input, so it doesn't change
+// when something else is plugged into the output it was copied from
+func (vs *Kramer4x4) LockEDID(ctx context.Context, input string, locked bool) error {
+	return vs.lockEDID(ctx, input, locked)
+}
+
+// EDIDLocks returns whether the EDID of each of inputs is locked
+func (vs *Kramer4x4) EDIDLocks(ctx context.Context, inputs []string) (map[string]bool, error) {
+	return vs.edidLocks(ctx, inputs)
+}
+
+// GetEDIDSource returns where the EDID of input was copied from
+func (vs *Kramer4x4) GetEDIDSource(ctx context.Context, input string) (EDIDSource, error) {
+	return vs.edidSource(ctx, input)
+}
+
+// ReadEDID downloads the raw EDID of src (#GEDID). Use ParseEDID to decode it.
+func (vs *Kramer4x4) ReadEDID(ctx context.Context, src EDIDSource) ([]byte, error) {
+	return vs.readEDID(ctx, src)
+}
+
+// WriteEDID uploads a raw EDID to each of inputs (#LDEDID)
+func (vs *Kramer4x4) WriteEDID(ctx context.Context, edid []byte, inputs ...string) error {
+	return vs.writeEDID(ctx, edid, inputs)
+}
+
+// CopyEDID copies the EDID from src to each of inputs (#CPEDID)
+func (vsdsp *KramerVP558) CopyEDID(ctx context.Context, src EDIDSource, inputs ...string) error {
+	return vsdsp.copyEDID(ctx, src, inputs)
+}
+
+// LockEDID locks (or unlocks) the EDID of input, so it doesn't change
+// when something else is plugged into the output it was copied from
+func (vsdsp *KramerVP558) LockEDID(ctx context.Context, input string, locked bool) error {
+	return vsdsp.lockEDID(ctx, input, locked)
+}
+
+// EDIDLocks returns whether the EDID of each of inputs is locked
+func (vsdsp *KramerVP558) EDIDLocks(ctx context.Context, inputs []string) (map[string]bool, error) {
+	return vsdsp.edidLocks(ctx, inputs)
+}
+
+// GetEDIDSource returns where the EDID of input was copied from
+func (vsdsp *KramerVP558) GetEDIDSource(ctx context.Context, input string) (EDIDSource, error) {
+	return vsdsp.edidSource(ctx, input)
+}
+
+// ReadEDID downloads the raw EDID of src (#GEDID). Use ParseEDID to decode it.
+func (vsdsp *KramerVP558) ReadEDID(ctx context.Context, src EDIDSource) ([]byte, error) {
+	return vsdsp.readEDID(ctx, src)
+}
+
+// WriteEDID uploads a raw EDID to each of inputs (#LDEDID)
+func (vsdsp *KramerVP558) WriteEDID(ctx context.Context, edid []byte, inputs ...string) error {
+	return vsdsp.writeEDID(ctx, edid, inputs)
+}
+
+// edidPort converts src's port to the device's numbering
+func (d *Device) edidPort(ctx context.Context, src EDIDSource) (int, error) {
+	if src.Type == EDIDDefault || src.Type == EDIDCustom {
+		return src.Port, nil
+	}
+
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := dims.Inputs
+	if src.Type == EDIDFromOutput {
+		count = dims.Outputs
+	}
+
+	p, err := parsePort(src.Type.String(), strconv.Itoa(src.Port), count)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.devicePort(p), nil
+}
+
+// edidInput converts input to the device's numbering
+func (d *Device) edidInput(ctx context.Context, input string) (int, error) {
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := parsePort("input", input, dims.Inputs)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.devicePort(i), nil
+}
+
+// inputBitmap returns the hex bitmap of inputs used by #CPEDID and #LDEDID,
+// e.g. "0x5" for inputs 0 and 2
+func (d *Device) inputBitmap(ctx context.Context, inputs []string) (string, error) {
+	if len(inputs) == 0 {
+		return "", fmt.Errorf("no inputs given")
+	}
+
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var bitmap uint64
+	for _, input := range inputs {
+		i, err := parsePort("input", input, dims.Inputs)
+		if err != nil {
+			return "", err
+		}
+
+		bitmap |= 1 << uint(i)
+	}
+
+	return fmt.Sprintf("0x%X", bitmap), nil
+}
+
+func (d *Device) copyEDID(ctx context.Context, src EDIDSource, inputs []string) error {
+	port, err := d.edidPort(ctx, src)
+	if err != nil {
+		return err
+	}
+
+	bitmap, err := d.inputBitmap(ctx, inputs)
+	if err != nil {
+		return err
+	}
+
+	cmd := NewCommand("CPEDID", strconv.Itoa(int(src.Type)), strconv.Itoa(port), strconv.Itoa(int(EDIDFromInput)), bitmap)
+	if _, err := d.Do(ctx, cmd); err != nil {
+		return fmt.Errorf("unable to copy EDID: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Device) lockEDID(ctx context.Context, input string, locked bool) error {
+	port, err := d.edidInput(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	state := "0"
+	if locked {
+		state = "1"
+	}
+
+	if _, err := d.Do(ctx, NewCommand("LOCK-EDID", strconv.Itoa(port), state)); err != nil {
+		return fmt.Errorf("unable to lock EDID: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Device) edidLocks(ctx context.Context, inputs []string) (map[string]bool, error) {
+	var cmds []Command
+	for _, input := range inputs {
+		port, err := d.edidInput(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		cmds = append(cmds, NewQuery("LOCK-EDID", strconv.Itoa(port)))
+	}
+
+	replies, err := d.Batch(ctx, cmds...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get EDID locks: %w", err)
+	}
+
+	toReturn := make(map[string]bool, len(inputs))
+	for i, input := range inputs {
+		// e.g. "~01@LOCK-EDID 1,1"
+		reply := replies[i]
+		if len(reply.Params) != 2 {
+			return nil, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		toReturn[input] = reply.Params[1] == "1" || strings.EqualFold(reply.Params[1], "ON")
+	}
+
+	return toReturn, nil
+}
+
+// edidSource parses the reply to #EDID-SRC?, e.g. "~01@EDID-SRC 1,1,2" for
+// input 1 having the EDID of output 2
+func (d *Device) edidSource(ctx context.Context, input string) (EDIDSource, error) {
+	port, err := d.edidInput(ctx, input)
+	if err != nil {
+		return EDIDSource{}, err
+	}
+
+	reply, err := d.Do(ctx, NewQuery("EDID-SRC", strconv.Itoa(port)))
+	if err != nil {
+		return EDIDSource{}, fmt.Errorf("unable to get EDID source: %w", err)
+	}
+
+	if len(reply.Params) != 3 {
+		return EDIDSource{}, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+	}
+
+	typ, err := strconv.Atoi(reply.Params[1])
+	if err != nil {
+		return EDIDSource{}, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+	}
+
+	n, err := strconv.Atoi(reply.Params[2])
+	if err != nil {
+		return EDIDSource{}, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+	}
+
+	src := EDIDSource{Type: EDIDSourceType(typ), Port: n}
+	if src.Type == EDIDFromInput || src.Type == EDIDFromOutput {
+		src.Port = d.localPort(n)
+	}
+
+	return src, nil
+}
+
+// readEDID sends #GEDID, which the device answers with "~01@GEDID type,port,size"
+// followed by size bytes of EDID
+func (d *Device) readEDID(ctx context.Context, src EDIDSource) ([]byte, error) {
+	port, err := d.edidPort(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := NewCommand("GEDID", strconv.Itoa(int(src.Type)), strconv.Itoa(port)).ToMachine(machineNumber(ctx, d.machine))
+	if !d.profile.Supports(cmd.Name) {
+		return nil, &Error{Code: ErrCommandNotAvailable.Code, Command: cmd.Name}
+	}
+
+	var edid []byte
+
+	err = d.transfer(ctx, func(conn connpool.Conn) error {
+		deadline := time.Now().Add(_edidTimeout)
+
+		if err := writeAll(conn, cmd.Bytes(), deadline); err != nil {
+			return err
+		}
+
+		reply, err := readReplyTo(conn, cmd.Name, deadline, d.events.skipped)
+		if err != nil {
+			return err
+		}
+
+		if err := reply.Err(); err != nil {
+			return err
+		}
+
+		if len(reply.Params) != 3 {
+			return fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		size, err := strconv.Atoi(reply.Params[2])
+		if err != nil || size <= 0 {
+			return fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		edid = make([]byte, size)
+		_ = conn.SetReadDeadline(deadline)
+
+		if _, err := io.ReadFull(conn, edid); err != nil {
+			return fmt.Errorf("unable to read EDID: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read EDID: %w", err)
+	}
+
+	return edid, nil
+}
+
+// writeEDID sends #LDEDID, waits for the device to be READY, and then sends the
+// EDID in packets of a 2 byte packet number (starting at 1), a 2 byte length, the
+// data and a 2 byte CRC, waiting for the device to acknowledge each packet
+func (d *Device) writeEDID(ctx context.Context, edid []byte, inputs []string) error {
+	if len(edid) == 0 || len(edid)%_edidBlockSize != 0 {
+		return fmt.Errorf("EDID must be a multiple of %d bytes long (got %d)", _edidBlockSize, len(edid))
+	}
+
+	bitmap, err := d.inputBitmap(ctx, inputs)
+	if err != nil {
+		return err
+	}
+
+	cmd := NewCommand("LDEDID", strconv.Itoa(int(EDIDFromInput)), bitmap, strconv.Itoa(len(edid)), "0").ToMachine(machineNumber(ctx, d.machine))
+	if !d.profile.Supports(cmd.Name) {
+		return &Error{Code: ErrCommandNotAvailable.Code, Command: cmd.Name}
+	}
+
+	err = d.transfer(ctx, func(conn connpool.Conn) error {
+		deadline := time.Now().Add(_edidTimeout)
+
+		if err := writeAll(conn, cmd.Bytes(), deadline); err != nil {
+			return err
+		}
+
+		reply, err := readReplyTo(conn, cmd.Name, deadline, d.events.skipped)
+		switch {
+		case err != nil:
+			return err
+		case reply.Err() != nil:
+			return reply.Err()
+		case !strings.HasSuffix(reply.Value(), "READY"):
+			return fmt.Errorf("device isn't ready for EDID: %s", reply)
+		}
+
+		for _, packet := range edidPackets(edid) {
+			deadline = time.Now().Add(_edidTimeout)
+			if err := writeAll(conn, packet, deadline); err != nil {
+				return err
+			}
+
+			// e.g. "~01@LDEDID 1 OK"
+			reply, err := readReplyTo(conn, cmd.Name, deadline, d.events.skipped)
+			if err != nil {
+				return err
+			}
+
+			if err := reply.Err(); err != nil {
+				return err
+			}
+		}
+
+		// the device acknowledges the whole command once it has every packet
+		reply, err = readReplyTo(conn, cmd.Name, deadline, d.events.skipped)
+		if err != nil {
+			return err
+		}
+
+		return reply.Err()
+	})
+	if err != nil {
+		return fmt.Errorf("unable to write EDID: %w", err)
+	}
+
+	return nil
+}
+
+// edidPackets splits edid into packets of up to _edidPacketSize bytes
+func edidPackets(edid []byte) [][]byte {
+	var packets [][]byte
+	for id, off := 1, 0; off < len(edid); id++ {
+		end := off + _edidPacketSize
+		if end > len(edid) {
+			end = len(edid)
+		}
+
+		packets = append(packets, edidPacket(id, edid[off:end]))
+		off = end
+	}
+
+	return packets
+}
+
+// edidPacket frames data as packet number id for #LDEDID
+func edidPacket(id int, data []byte) []byte {
+	packet := make([]byte, 4, len(data)+6)
+	binary.BigEndian.PutUint16(packet[0:], uint16(id))
+	binary.BigEndian.PutUint16(packet[2:], uint16(len(data)))
+	packet = append(packet, data...)
+
+	crc := crc16(packet)
+	return append(packet, byte(crc>>8), byte(crc))
+}
+
+// crc16 is CRC-16/XMODEM, which Protocol 3000 uses for file transfers
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
+
+func writeAll(conn connpool.Conn, b []byte, deadline time.Time) error {
+	_ = conn.SetWriteDeadline(deadline)
+
+	n, err := conn.Write(b)
+	switch {
+	case err != nil:
+		return err
+	case n != len(b):
+		return fmt.Errorf("wrote %v/%v bytes of command 0x%x", n, len(b), b)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package kramer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+)
+
+// _testEDID is a 1080p60 Dell U2718Q EDID with a CEA-861 extension that lists LPCM audio
+var _testEDID = []byte{
+	0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00, 0x10, 0xac, 0xc5, 0xa0, 0x4c, 0x53, 0x4b, 0x41,
+	0x1a, 0x1c, 0x01, 0x03, 0x80, 0x3c, 0x22, 0x78, 0xea, 0xee, 0x95, 0xa3, 0x54, 0x4c, 0x99, 0x26,
+	0x0f, 0x50, 0x54, 0xa5, 0x4b, 0x00, 0x71, 0x4f, 0x81, 0x80, 0xa9, 0xc0, 0xd1, 0xc0, 0x01, 0x01,
+	0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x02, 0x3a, 0x80, 0x18, 0x71, 0x38, 0x2d, 0x40, 0x58, 0x2c,
+	0x45, 0x00, 0x56, 0x50, 0x21, 0x00, 0x00, 0x1e, 0x00, 0x00, 0x00, 0xff, 0x00, 0x43, 0x46, 0x56,
+	0x39, 0x4e, 0x37, 0x41, 0x52, 0x30, 0x59, 0x44, 0x4c, 0x0a, 0x00, 0x00, 0x00, 0xfc, 0x00, 0x44,
+	0x45, 0x4c, 0x4c, 0x20, 0x55, 0x32, 0x37, 0x31, 0x38, 0x51, 0x0a, 0x20, 0x00, 0x00, 0x00, 0xfd,
+	0x00, 0x38, 0x4c, 0x1e, 0x53, 0x11, 0x00, 0x0a, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x01, 0xbe,
+	0x02, 0x03, 0x08, 0x00, 0x23, 0x09, 0x07, 0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xb9,
+}
+
+func TestParseEDID(t *testing.T) {
+	edid, err := ParseEDID(_testEDID)
+	if err != nil {
+		t.Fatalf("ParseEDID: %s", err)
+	}
+
+	want := EDID{
+		Manufacturer:  "DEL",
+		ProductCode:   0xa0c5,
+		SerialNumber:  0x414b534c,
+		Week:          26,
+		Year:          2018,
+		Version:       "1.3",
+		MonitorName:   "DELL U2718Q",
+		MonitorSerial: "CFV9N7AR0YDL",
+		Extensions:    1,
+		Audio:         true,
+	}
+
+	got := *edid
+	got.PreferredTiming = nil
+	if got != want {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+
+	timing := edid.PreferredTiming
+	switch {
+	case timing == nil:
+		t.Fatalf("no preferred timing")
+	case timing.PixelClock != 148500 || timing.HActive != 1920 || timing.VActive != 1080 || timing.Interlaced:
+		t.Errorf("got preferred timing %+v", *timing)
+	case math.Abs(timing.RefreshRate-60) > 0.01:
+		t.Errorf("got refresh rate %v, want 60", timing.RefreshRate)
+	}
+
+	// just the base block
+	edid, err = ParseEDID(_testEDID[:_edidBlockSize])
+	if err != nil {
+		t.Fatalf("ParseEDID(base block): %s", err)
+	}
+
+	if edid.MonitorName != "DELL U2718Q" || edid.Audio {
+		t.Errorf("got %+v from the base block", *edid)
+	}
+}
+
+func TestParseEDIDCorrupted(t *testing.T) {
+	badHeader := append([]byte(nil), _testEDID...)
+	badHeader[1] = 0
+
+	badChecksum := append([]byte(nil), _testEDID...)
+	badChecksum[_edidBlockSize+10]++
+
+	tests := []struct {
+		name string
+		edid []byte
+	}{
+		{"empty", nil},
+		{"short", _testEDID[:100]},
+		{"partial block", _testEDID[:200]},
+		{"bad header", badHeader},
+		{"bad checksum", badChecksum},
+	}
+
+	for _, tt := range tests {
+		if _, err := ParseEDID(tt.edid); !errors.Is(err, ErrEDIDCorrupted) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, ErrEDIDCorrupted)
+		}
+	}
+}
+
+func TestCRC16(t *testing.T) {
+	if crc := crc16([]byte("123456789")); crc != 0x31c3 {
+		t.Errorf("got %#04x, want 0x31c3", crc)
+	}
+}
+
+func TestEDIDPackets(t *testing.T) {
+	tests := []struct {
+		size    int
+		lengths []int
+	}{
+		{64, []int{64}},
+		{128, []int{64, 64}},
+		{200, []int{64, 64, 64, 8}},
+		{256, []int{64, 64, 64, 64}},
+	}
+
+	for _, tt := range tests {
+		edid := make([]byte, tt.size)
+		for i := range edid {
+			edid[i] = byte(i)
+		}
+
+		packets := edidPackets(edid)
+		if len(packets) != len(tt.lengths) {
+			t.Errorf("%d bytes: got %d packets, want %d", tt.size, len(packets), len(tt.lengths))
+			continue
+		}
+
+		var data []byte
+		for i, packet := range packets {
+			n := tt.lengths[i]
+			switch {
+			case len(packet) != n+6:
+				t.Errorf("%d bytes: packet %d is %d bytes, want %d", tt.size, i+1, len(packet), n+6)
+				continue
+			case binary.BigEndian.Uint16(packet[0:]) != uint16(i+1):
+				t.Errorf("%d bytes: packet %d has number %d", tt.size, i+1, binary.BigEndian.Uint16(packet[0:]))
+			case binary.BigEndian.Uint16(packet[2:]) != uint16(n):
+				t.Errorf("%d bytes: packet %d has length %d, want %d", tt.size, i+1, binary.BigEndian.Uint16(packet[2:]), n)
+			case binary.BigEndian.Uint16(packet[n+4:]) != crc16(packet[:n+4]):
+				t.Errorf("%d bytes: packet %d has a bad CRC", tt.size, i+1)
+			}
+
+			data = append(data, packet[4:n+4]...)
+		}
+
+		if !bytes.Equal(data, edid) {
+			t.Errorf("%d bytes: packets don't add up to the EDID", tt.size)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package kramer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+const _edidBlockSize = 128
+
+var _edidHeader = []byte{0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00}
+
+// EDID is the decoded base block (and CEA extension, if there is one) of an EDID
+type EDID struct {
+	// Manufacturer is the three letter PNP ID, e.g. "SAM"
+	Manufacturer string
+	ProductCode  uint16
+	SerialNumber uint32
+	Week         int
+	Year         int
+	Version      string
+
+	// MonitorName and MonitorSerial come from the display descriptors, if present
+	MonitorName   string
+	MonitorSerial string
+
+	// PreferredTiming is the first detailed timing descriptor
+	PreferredTiming *Timing
+
+	Extensions int
+
+	// Audio is true if a CEA-861 extension says the display supports basic audio,
+	// or lists any audio formats
+	Audio bool
+}
+
+// Timing is a detailed timing descriptor
+type Timing struct {
+	// PixelClock is in kHz
+	PixelClock  int
+	HActive     int
+	VActive     int
+	Interlaced  bool
+	RefreshRate float64
+}
+
+func (t Timing) String() string {
+	scan := "p"
+	if t.Interlaced {
+		scan = "i"
+	}
+
+	return fmt.Sprintf("%dx%d%s@%.2fHz", t.HActive, t.VActive, scan, t.RefreshRate)
+}
+
+// ParseEDID decodes raw EDID bytes, as returned by ReadEDID. Malformed EDIDs and
+// bad checksums return an error wrapping ErrEDIDCorrupted.
+func ParseEDID(b []byte) (*EDID, error) {
+	if len(b) < _edidBlockSize || len(b)%_edidBlockSize != 0 {
+		return nil, fmt.Errorf("EDID is %d bytes, not a multiple of %d: %w", len(b), _edidBlockSize, ErrEDIDCorrupted)
+	}
+
+	if !bytes.Equal(b[:8], _edidHeader) {
+		return nil, fmt.Errorf("invalid EDID header: %w", ErrEDIDCorrupted)
+	}
+
+	for i := 0; i < len(b); i += _edidBlockSize {
+		if sum := checksum(b[i : i+_edidBlockSize]); sum != 0 {
+			return nil, fmt.Errorf("block %d has a bad checksum: %w", i/_edidBlockSize, ErrEDIDCorrupted)
+		}
+	}
+
+	// manufacturer id is three 5 bit letters, 1 == 'A'
+	id := binary.BigEndian.Uint16(b[8:10])
+	edid := &EDID{
+		Manufacturer: string([]byte{
+			byte(id>>10&0x1f) + 'A' - 1,
+			byte(id>>5&0x1f) + 'A' - 1,
+			byte(id&0x1f) + 'A' - 1,
+		}),
+		ProductCode:  binary.LittleEndian.Uint16(b[10:12]),
+		SerialNumber: binary.LittleEndian.Uint32(b[12:16]),
+		Week:         int(b[16]),
+		Year:         int(b[17]) + 1990,
+		Version:      fmt.Sprintf("%d.%d", b[18], b[19]),
+		Extensions:   int(b[126]),
+	}
+
+	// four 18 byte descriptors, either detailed timings or display descriptors
+	for i := 54; i < 126; i += 18 {
+		desc := b[i : i+18]
+
+		if desc[0] != 0 || desc[1] != 0 {
+			if edid.PreferredTiming == nil {
+				edid.PreferredTiming = parseTiming(desc)
+			}
+
+			continue
+		}
+
+		switch desc[3] {
+		case 0xfc:
+			edid.MonitorName = descriptorText(desc)
+		case 0xff:
+			edid.MonitorSerial = descriptorText(desc)
+		}
+	}
+
+	for i := _edidBlockSize; i < len(b); i += _edidBlockSize {
+		if ceaAudio(b[i : i+_edidBlockSize]) {
+			edid.Audio = true
+		}
+	}
+
+	return edid, nil
+}
+
+func checksum(block []byte) byte {
+	var sum byte
+	for _, c := range block {
+		sum += c
+	}
+
+	return sum
+}
+
+func parseTiming(desc []byte) *Timing {
+	t := &Timing{
+		PixelClock: int(binary.LittleEndian.Uint16(desc[0:2])) * 10,
+		HActive:    int(desc[2]) | int(desc[4]&0xf0)<<4,
+		VActive:    int(desc[5]) | int(desc[7]&0xf0)<<4,
+		Interlaced: desc[17]&0x80 != 0,
+	}
+
+	hTotal := t.HActive + (int(desc[3]) | int(desc[4]&0x0f)<<8)
+	vTotal := t.VActive + (int(desc[6]) | int(desc[7]&0x0f)<<8)
+
+	if hTotal > 0 && vTotal > 0 {
+		t.RefreshRate = float64(t.PixelClock) * 1000 / float64(hTotal*vTotal)
+	}
+
+	// interlaced timings describe a single field
+	if t.Interlaced {
+		t.VActive *= 2
+	}
+
+	return t
+}
+
+// descriptorText returns the text of a display descriptor, which is terminated by a newline
+func descriptorText(desc []byte) string {
+	text := desc[5:18]
+	if i := bytes.IndexByte(text, '\n'); i >= 0 {
+		text = text[:i]
+	}
+
+	return strings.TrimSpace(string(text))
+}
+
+// ceaAudio returns whether a CEA-861 extension block says the display supports audio
+func ceaAudio(block []byte) bool {
+	if block[0] != 0x02 {
+		return false
+	}
+
+	if block[1] >= 2 && block[3]&0x40 != 0 {
+		return true
+	}
+
+	// data blocks run from byte 4 until the offset to the detailed timings
+	end := int(block[2])
+	if end > _edidBlockSize-1 {
+		end = _edidBlockSize - 1
+	}
+
+	for i := 4; i < end; {
+		tag, n := block[i]>>5, int(block[i]&0x1f)
+		if tag == 1 && n > 0 {
+			return true
+		}
+
+		i += n + 1
+	}
+
+	return false
+}
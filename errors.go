@@ -0,0 +1,89 @@
+package kramer
+
+import "fmt"
+
+// Error is a Protocol 3000 error (ERR nnn) returned by a device.
+// Use errors.Is with one of the Err* values to check for a specific code,
+// or errors.As to get the code and the command that failed.
+type Error struct {
+	Code    int
+	Command string
+}
+
+// Protocol 3000 error codes
+var (
+	ErrSyntax              = &Error{Code: 1}
+	ErrCommandNotAvailable = &Error{Code: 2}
+	ErrParameterOutOfRange = &Error{Code: 3}
+	ErrUnauthorized        = &Error{Code: 4}
+	ErrInternalFirmware    = &Error{Code: 5}
+	ErrBusy                = &Error{Code: 6}
+	ErrWrongCRC            = &Error{Code: 7}
+	ErrTimedOut            = &Error{Code: 8}
+	ErrNotEnoughSpace      = &Error{Code: 10}
+	ErrFSNotEnoughSpace    = &Error{Code: 11}
+	ErrFileNotExists       = &Error{Code: 12}
+	ErrFileCantCreate      = &Error{Code: 13}
+	ErrFileCantOpen        = &Error{Code: 14}
+	ErrFeatureNotSupported = &Error{Code: 15}
+	ErrPacketCRC           = &Error{Code: 17}
+	ErrPacketMissed        = &Error{Code: 18}
+	ErrPacketSize          = &Error{Code: 19}
+	ErrEDIDCorrupted       = &Error{Code: 30}
+	ErrDeviceSpecific      = &Error{Code: 31}
+	ErrSameCRC             = &Error{Code: 32}
+	ErrWrongMode           = &Error{Code: 33}
+	ErrNotConfigured       = &Error{Code: 34}
+)
+
+var errorDescriptions = map[int]string{
+	1:  "protocol syntax error",
+	2:  "command not available",
+	3:  "parameter out of range",
+	4:  "unauthorized access",
+	5:  "internal firmware error",
+	6:  "protocol busy",
+	7:  "wrong CRC",
+	8:  "timed out",
+	10: "not enough space for data",
+	11: "not enough space on file system",
+	12: "file does not exist",
+	13: "file can't be created",
+	14: "file can't be opened",
+	15: "feature is not supported",
+	17: "packet CRC error",
+	18: "packet number isn't expected",
+	19: "packet size is wrong",
+	30: "EDID corrupted",
+	31: "device specific error",
+	32: "file has the same CRC",
+	33: "wrong operation mode",
+	34: "device/chip was not initialized",
+}
+
+// Description returns a human readable description of the error code
+func (e *Error) Description() string {
+	if desc, ok := errorDescriptions[e.Code]; ok {
+		return desc
+	}
+
+	return "unknown error"
+}
+
+func (e *Error) Error() string {
+	if len(e.Command) == 0 {
+		return fmt.Sprintf("ERR %03d: %s", e.Code, e.Description())
+	}
+
+	return fmt.Sprintf("ERR %03d: %s (command %s)", e.Code, e.Description(), e.Command)
+}
+
+// Is reports whether target is an *Error with the same code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+
+	return t.Code == e.Code
+}
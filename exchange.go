@@ -0,0 +1,220 @@
+package kramer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/byuoitav/connpool"
+)
+
+// exchange writes cmds to conn as a single frame and reads until the reply to
+// each command arrives, returning the replies in the same order as cmds.
+// Notifications read along the way are passed to skipped.
+func exchange(conn connpool.Conn, cmds []Command, skipped func(Reply)) ([]Reply, error) {
+	b := encodeBatch(cmds)
+
+	_ = conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	readDur := time.Now().Add(3 * time.Second)
+
+	n, err := conn.Write(b)
+	switch {
+	case err != nil:
+		return nil, err
+	case n != len(b):
+		return nil, fmt.Errorf("wrote %v/%v bytes of command 0x%x", n, len(b), b)
+	}
+
+	replies := make([]Reply, len(cmds))
+	answered := make([]bool, len(cmds))
+
+	for remaining := len(cmds); remaining > 0; {
+		line, err := conn.ReadUntil(LINE_FEED, readDur)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read response: %w", err)
+		}
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		reply, err := ParseReply(line)
+		if err != nil {
+			return nil, err
+		}
+
+		i := replyIndex(cmds, answered, reply)
+		if i < 0 {
+			skipped(reply)
+			continue
+		}
+
+		if cmds[i].Query {
+			reply = queryReply(reply)
+		}
+
+		replies[i] = reply
+		answered[i] = true
+		remaining--
+	}
+
+	return replies, nil
+}
+
+// connError is an error after which a connection can't be trusted to be in
+// sync with the device. It is a permanent net.Error, so the pool closes the
+// connection instead of reusing it.
+type connError struct {
+	err error
+}
+
+func (e *connError) Error() string {
+	return e.err.Error()
+}
+
+func (e *connError) Unwrap() error {
+	return e.err
+}
+
+func (e *connError) Timeout() bool {
+	var nerr net.Error
+	return errors.As(e.err, &nerr) && nerr.Timeout()
+}
+
+func (e *connError) Temporary() bool {
+	return false
+}
+
+// syncError is an error after which a connection is out of sync with the
+// device even though the device is answering, e.g. a reply that can't be parsed.
+// The connection is closed like after a connError, but the command isn't
+// retried and the device isn't counted as unavailable.
+type syncError struct {
+	err error
+}
+
+func (e *syncError) Error() string {
+	return e.err.Error()
+}
+
+func (e *syncError) Unwrap() error {
+	return e.err
+}
+
+func (e *syncError) Timeout() bool {
+	return false
+}
+
+func (e *syncError) Temporary() bool {
+	return false
+}
+
+// replyIndex returns the index of the first unanswered command in cmds that reply answers, or -1
+func replyIndex(cmds []Command, answered []bool, reply Reply) int {
+	for i, cmd := range cmds {
+		if !answered[i] && cmd.isReply(reply) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// roundTrip is exchange for a connection that hasn't been handed to the pool yet
+func roundTrip(conn *bufferedConn, cmd Command) (Reply, error) {
+	if err := cmd.Validate(); err != nil {
+		return Reply{}, err
+	}
+
+	b := cmd.Bytes()
+
+	_ = conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	defer conn.SetDeadline(time.Time{})
+
+	n, err := conn.Write(b)
+	switch {
+	case err != nil:
+		return Reply{}, err
+	case n != len(b):
+		return Reply{}, fmt.Errorf("wrote %v/%v bytes of command 0x%x", n, len(b), b)
+	}
+
+	for {
+		line, err := conn.readLine()
+		if err != nil {
+			return Reply{}, fmt.Errorf("unable to read response: %w", err)
+		}
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		reply, err := ParseReply(line)
+		if err != nil {
+			return reply, err
+		}
+
+		if cmd.isReply(reply) {
+			return reply, nil
+		}
+	}
+}
+
+// _readBufferSize is big enough for any UDP datagram
+const _readBufferSize = 64 * 1024
+
+// bufferedConn is a connection with a read buffer that lasts as long as the
+// connection does, so a reply can be read a line at a time without losing
+// anything the device sent after it. Reads from a packet connection (UDP)
+// take a whole datagram, so the buffer is big enough to hold one.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func newBufferedConn(conn net.Conn) *bufferedConn {
+	return &bufferedConn{
+		Conn: conn,
+		r:    bufio.NewReaderSize(conn, _readBufferSize),
+	}
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+func (c *bufferedConn) readLine() ([]byte, error) {
+	return c.r.ReadBytes(LINE_FEED)
+}
+
+// readReplyTo reads from conn until a reply to the command name arrives, for
+// commands whose replies don't fit isReply (e.g. "~01@LDEDID 1,0x1,256,0 READY").
+// Other replies and notifications read along the way are passed to skipped.
+func readReplyTo(conn connpool.Conn, name string, deadline time.Time, skipped func(Reply)) (Reply, error) {
+	for {
+		line, err := conn.ReadUntil(LINE_FEED, deadline)
+		if err != nil {
+			return Reply{}, fmt.Errorf("unable to read response: %w", err)
+		}
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		reply, err := ParseReply(line)
+		if err != nil {
+			return reply, err
+		}
+
+		if strings.EqualFold(reply.Command, name) || (reply.Status == StatusErr && len(reply.Command) == 0) {
+			return reply, nil
+		}
+
+		skipped(reply)
+	}
+}
@@ -0,0 +1,58 @@
+package kramer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const _defaultHandshakeTimeout = 3 * time.Second
+
+// handshake sends an empty command ("#") and waits for the device to answer
+// "~nn@ OK", so that nothing the device sends when the connection is opened
+// (e.g. a welcome banner) is read as the reply to the first real command.
+// Anything that isn't a Protocol 3000 reply read before the answer is
+// returned as the banner.
+func handshake(ctx context.Context, conn *bufferedConn, timeout time.Duration) (string, error) {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	_ = conn.SetDeadline(deadline)
+	defer conn.SetDeadline(time.Time{})
+
+	if _, err := conn.Write([]byte{'#', CARRIAGE_RETURN}); err != nil {
+		return "", fmt.Errorf("unable to send handshake: %w", err)
+	}
+
+	var banner []string
+	for {
+		line, err := conn.readLine()
+		if err != nil {
+			var nerr net.Error
+			if errors.As(err, &nerr) && nerr.Timeout() {
+				return strings.Join(banner, "\n"), fmt.Errorf("device didn't answer handshake within %v: %w", timeout, err)
+			}
+
+			return strings.Join(banner, "\n"), fmt.Errorf("unable to read handshake: %w", err)
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		reply, err := ParseReply(line)
+		switch {
+		case err != nil:
+			banner = append(banner, string(line))
+		case len(reply.Command) == 0 && reply.Status != StatusNone:
+			return strings.Join(banner, "\n"), reply.Err()
+		}
+	}
+}
@@ -45,305 +45,82 @@ type CommandInfo struct {
 	ReadWelcome     bool
 }
 
-func (vs *Kramer4x4) hardwareCommand(ctx context.Context, commandType, param string) (string, error) {
-	var cmd []byte
+func (d *Device) hardwareCommand(ctx context.Context, commandType, param string) (string, error) {
+	cmd := NewQuery(commandType)
 
 	if len(param) > 0 {
 		num, _ := strconv.Atoi(param)
-		cmd = []byte(fmt.Sprintf("#%s? %d\r\n", commandType, num))
-	} else {
-		cmd = []byte(fmt.Sprintf("#%s?\r\n", commandType))
+		cmd.Params = []string{strconv.Itoa(num)}
 	}
 
-	resp, err := vs.SendCommand(ctx, cmd)
-
+	reply, err := d.Do(ctx, cmd)
 	if err != nil {
 		return "", fmt.Errorf("unable to send command: %w", err)
 	}
-	resps := string(resp)
-	resps = strings.Split(resps, fmt.Sprintf("%s", commandType))[1]
-	resps = strings.Trim(resps, "\r\n")
-	resps = strings.TrimSpace(resps)
-
-	return resps, nil
-}
-
-func (vs *Kramer4x4) GetHardwareInfo(ctx context.Context) (structs.HardwareInfo, error) {
-	var toReturn structs.HardwareInfo
-	// get the hostname
-	addr, e := net.LookupAddr(vs.Address)
-	if e != nil {
-		toReturn.Hostname = vs.Address
-	} else {
-		toReturn.Hostname = strings.Trim(addr[0], ".")
-	}
-
-	// get build date
-	buildDate, err := vs.hardwareCommand(ctx, BuildDate, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get build date from %s", vs.Address)
-	}
-
-	toReturn.BuildDate = buildDate
-
-	// get device model
-	model, err := vs.hardwareCommand(ctx, Model, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get model number from %s", vs.Address)
-	}
-
-	toReturn.ModelName = model
-
-	// get device protocol version
-	protocol, err := vs.hardwareCommand(ctx, ProtocolVersion, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get protocol version from %s", vs.Address)
-	}
-
-	toReturn.ProtocolVersion = strings.Trim(protocol, "3000:")
-
-	// get firmware version
-	firmware, err := vs.hardwareCommand(ctx, FirmwareVersion, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get firmware version from %s", vs.Address)
-	}
-
-	toReturn.FirmwareVersion = firmware
-
-	// get serial number
-	serial, err := vs.hardwareCommand(ctx, SerialNumber, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get serial number from %s", vs.Address)
-	}
-
-	toReturn.SerialNumber = serial
-
-	// get IP address
-	ipAddress, err := vs.hardwareCommand(ctx, IPAddress, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get IP address from %s... ironic...", vs.Address)
-	}
-
-	// get gateway
-	gateway, err := vs.hardwareCommand(ctx, Gateway, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get the gateway address from %s", vs.Address)
-	}
-
-	// get MAC address
-	mac, err := vs.hardwareCommand(ctx, MACAddress, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get the MAC address from %s", vs.Address)
-	}
 
-	// set network information
-	toReturn.NetworkInfo = structs.NetworkInfo{
-		IPAddress:  ipAddress,
-		MACAddress: mac,
-		Gateway:    gateway,
-	}
-
-	return toReturn, nil
+	return reply.Value(), nil
 }
 
-func (dsp *KramerAFM20DSP) hardwareCommand(ctx context.Context, commandType, param string) (string, error) {
-	var cmd []byte
-
-	if len(param) > 0 {
-		num, _ := strconv.Atoi(param)
-		cmd = []byte(fmt.Sprintf("#%s? %d\r\n", commandType, num))
-	} else {
-		cmd = []byte(fmt.Sprintf("#%s?\r\n", commandType))
-	}
-
-	resp, err := dsp.SendCommand(ctx, cmd)
+// hardwareQueries are the queries used to fill out structs.HardwareInfo
+var hardwareQueries = []string{BuildDate, Model, ProtocolVersion, FirmwareVersion, SerialNumber, IPAddress, Gateway, MACAddress}
 
-	if err != nil {
-		return "", fmt.Errorf("unable to send command: %w", err)
+func hardwareCommands() []Command {
+	cmds := make([]Command, len(hardwareQueries))
+	for i, q := range hardwareQueries {
+		cmds[i] = NewQuery(q)
 	}
-	resps := string(resp)
-	resps = strings.Split(resps, fmt.Sprintf("%s", commandType))[1]
-	resps = strings.Trim(resps, "\r\n")
-	resps = strings.TrimSpace(resps)
 
-	return resps, nil
+	return cmds
 }
 
-func (dsp *KramerAFM20DSP) GetHardwareInfo(ctx context.Context) (structs.HardwareInfo, error) {
+// newHardwareInfo builds the hardware info for the device at address from
+// the replies to hardwareCommands(). Fields whose query the device rejected are left empty.
+func newHardwareInfo(address string, replies []Reply) structs.HardwareInfo {
 	var toReturn structs.HardwareInfo
+
 	// get the hostname
-	addr, e := net.LookupAddr(dsp.Address)
+	addr, e := net.LookupAddr(address)
 	if e != nil {
-		toReturn.Hostname = dsp.Address
+		toReturn.Hostname = address
 	} else {
 		toReturn.Hostname = strings.Trim(addr[0], ".")
 	}
 
-	// get build date
-	buildDate, err := dsp.hardwareCommand(ctx, BuildDate, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get build date from %s", dsp.Address)
-	}
-
-	toReturn.BuildDate = buildDate
-
-	// get device model
-	model, err := dsp.hardwareCommand(ctx, Model, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get model number from %s", dsp.Address)
-	}
-
-	toReturn.ModelName = model
-
-	// get device protocol version
-	protocol, err := dsp.hardwareCommand(ctx, ProtocolVersion, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get protocol version from %s", dsp.Address)
+	values := make(map[string]string)
+	for i, reply := range replies {
+		if reply.Err() == nil {
+			values[hardwareQueries[i]] = reply.Value()
+		}
 	}
 
-	toReturn.ProtocolVersion = strings.Trim(protocol, "3000:")
-
-	// get firmware version
-	firmware, err := dsp.hardwareCommand(ctx, FirmwareVersion, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get firmware version from %s", dsp.Address)
-	}
-
-	toReturn.FirmwareVersion = firmware
-
-	// get serial number
-	serial, err := dsp.hardwareCommand(ctx, SerialNumber, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get serial number from %s", dsp.Address)
-	}
-
-	toReturn.SerialNumber = serial
-
-	// get IP address
-	ipAddress, err := dsp.hardwareCommand(ctx, IPAddress, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get IP address from %s... ironic...", dsp.Address)
-	}
-
-	// get gateway
-	gateway, err := dsp.hardwareCommand(ctx, Gateway, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get the gateway address from %s", dsp.Address)
-	}
-
-	// get MAC address
-	mac, err := dsp.hardwareCommand(ctx, MACAddress, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get the MAC address from %s", dsp.Address)
-	}
+	toReturn.BuildDate = values[BuildDate]
+	toReturn.ModelName = values[Model]
+	toReturn.ProtocolVersion = strings.TrimPrefix(values[ProtocolVersion], "3000:")
+	toReturn.FirmwareVersion = values[FirmwareVersion]
+	toReturn.SerialNumber = values[SerialNumber]
 
 	// set network information
 	toReturn.NetworkInfo = structs.NetworkInfo{
-		IPAddress:  ipAddress,
-		MACAddress: mac,
-		Gateway:    gateway,
+		IPAddress:  values[IPAddress],
+		MACAddress: values[MACAddress],
+		Gateway:    values[Gateway],
 	}
 
-	return toReturn, nil
+	return toReturn
 }
 
-func (vsdsp *KramerVP558) hardwareCommand(ctx context.Context, commandType, param string) (string, error) {
-	var cmd []byte
-
-	if len(param) > 0 {
-		num, _ := strconv.Atoi(param)
-		cmd = []byte(fmt.Sprintf("#%s? %d\r\n", commandType, num))
-	} else {
-		cmd = []byte(fmt.Sprintf("#%s?\r\n", commandType))
+// GetHardwareInfo returns the model, versions and network information of the device.
+// Information the device doesn't support (e.g. NET-GATE? on some models) is left empty.
+func (d *Device) GetHardwareInfo(ctx context.Context) (structs.HardwareInfo, error) {
+	// Batch returns the replies along with the first rejected one's error
+	replies, err := d.Batch(ctx, hardwareCommands()...)
+	if len(replies) != len(hardwareQueries) {
+		return structs.HardwareInfo{}, fmt.Errorf("failed to get hardware info from %s: %w", d.Address, err)
 	}
 
-	resp, err := vsdsp.SendCommand(ctx, cmd, false)
-
 	if err != nil {
-		return "", fmt.Errorf("unable to send command: %w", err)
-	}
-	resps := string(resp)
-	resps = strings.Split(resps, fmt.Sprintf("%s", commandType))[1]
-	resps = strings.Trim(resps, "\r\n")
-	resps = strings.TrimSpace(resps)
-
-	return resps, nil
-}
-
-func (vsdsp *KramerVP558) GetHardwareInfo(ctx context.Context) (structs.HardwareInfo, error) {
-	var toReturn structs.HardwareInfo
-	// get the hostname
-	addr, e := net.LookupAddr(vsdsp.Address)
-	if e != nil {
-		toReturn.Hostname = vsdsp.Address
-	} else {
-		toReturn.Hostname = strings.Trim(addr[0], ".")
-	}
-
-	// get build date
-	buildDate, err := vsdsp.hardwareCommand(ctx, BuildDate, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get build date from %s", vsdsp.Address)
-	}
-
-	toReturn.BuildDate = buildDate
-
-	// get device model
-	model, err := vsdsp.hardwareCommand(ctx, Model, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get model number from %s", vsdsp.Address)
-	}
-
-	toReturn.ModelName = model
-
-	// get device protocol version
-	protocol, err := vsdsp.hardwareCommand(ctx, ProtocolVersion, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get protocol version from %s", vsdsp.Address)
-	}
-
-	toReturn.ProtocolVersion = strings.Trim(protocol, "3000:")
-
-	// get firmware version
-	firmware, err := vsdsp.hardwareCommand(ctx, FirmwareVersion, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get firmware version from %s", vsdsp.Address)
-	}
-
-	toReturn.FirmwareVersion = firmware
-
-	// get serial number
-	serial, err := vsdsp.hardwareCommand(ctx, SerialNumber, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get serial number from %s", vsdsp.Address)
-	}
-
-	toReturn.SerialNumber = serial
-
-	// get IP address
-	ipAddress, err := vsdsp.hardwareCommand(ctx, IPAddress, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get IP address from %s... ironic...", vsdsp.Address)
-	}
-
-	// get gateway
-	gateway, err := vsdsp.hardwareCommand(ctx, Gateway, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get the gateway address from %s", vsdsp.Address)
-	}
-
-	// get MAC address
-	mac, err := vsdsp.hardwareCommand(ctx, MACAddress, "")
-	if err != nil {
-		return toReturn, fmt.Errorf("failed to get the MAC address from %s", vsdsp.Address)
-	}
-
-	// set network information
-	toReturn.NetworkInfo = structs.NetworkInfo{
-		IPAddress:  ipAddress,
-		MACAddress: mac,
-		Gateway:    gateway,
+		d.Log.Debugf("%s rejected some hardware info queries: %s", d.Address, err)
 	}
 
-	return toReturn, nil
+	return newHardwareInfo(d.Address, replies), nil
 }
@@ -0,0 +1,188 @@
+package kramer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// HDCPMode is whether an input advertises HDCP support to its source (#HDCP-MOD)
+type HDCPMode int
+
+const (
+	// HDCPModeOff tells the source the input doesn't support HDCP, so it sends unprotected content
+	HDCPModeOff HDCPMode = 0
+	// HDCPModeOn tells the source the input supports HDCP
+	HDCPModeOn HDCPMode = 1
+	// HDCPModeFollowOutput supports HDCP only if the display on the output does
+	HDCPModeFollowOutput HDCPMode = 3
+)
+
+func (m HDCPMode) String() string {
+	switch m {
+	case HDCPModeOff:
+		return "off"
+	case HDCPModeOn:
+		return "on"
+	case HDCPModeFollowOutput:
+		return "follow output"
+	default:
+		return "unknown"
+	}
+}
+
+// HDCPStatus is whether the content on each input and output is HDCP encrypted (#HDCP-STAT?)
+type HDCPStatus struct {
+	Inputs  map[string]bool
+	Outputs map[string]bool
+}
+
+// HDCP-STAT? takes whether the port is an input or an output
+const (
+	_hdcpInput  = "0"
+	_hdcpOutput = "1"
+)
+
+// HDCPStatus returns whether the content on every input and output is HDCP encrypted
+func (vs *Kramer4x4) HDCPStatus(ctx context.Context) (HDCPStatus, error) {
+	return vs.hdcpStatus(ctx)
+}
+
+// SetHDCPMode sets whether input advertises HDCP support to its source
+func (vs *Kramer4x4) SetHDCPMode(ctx context.Context, input string, mode HDCPMode) error {
+	return vs.setHDCPMode(ctx, input, mode)
+}
+
+// HDCPModes returns the HDCP mode of each of inputs
+func (vs *Kramer4x4) HDCPModes(ctx context.Context, inputs []string) (map[string]HDCPMode, error) {
+	return vs.hdcpModes(ctx, inputs)
+}
+
+// HDCPStatus returns whether the content on every input and output is HDCP encrypted
+func (vsdsp *KramerVP558) HDCPStatus(ctx context.Context) (HDCPStatus, error) {
+	return vsdsp.hdcpStatus(ctx)
+}
+
+// SetHDCPMode sets whether input advertises HDCP support to its source
+func (vsdsp *KramerVP558) SetHDCPMode(ctx context.Context, input string, mode HDCPMode) error {
+	return vsdsp.setHDCPMode(ctx, input, mode)
+}
+
+// HDCPModes returns the HDCP mode of each of inputs
+func (vsdsp *KramerVP558) HDCPModes(ctx context.Context, inputs []string) (map[string]HDCPMode, error) {
+	return vsdsp.hdcpModes(ctx, inputs)
+}
+
+func (d *Device) hdcpStatus(ctx context.Context) (HDCPStatus, error) {
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return HDCPStatus{}, err
+	}
+
+	var cmds []Command
+	for i := 0; i < dims.Inputs; i++ {
+		cmds = append(cmds, NewQuery("HDCP-STAT", _hdcpInput, strconv.Itoa(d.devicePort(i))))
+	}
+
+	for o := 0; o < dims.Outputs; o++ {
+		cmds = append(cmds, NewQuery("HDCP-STAT", _hdcpOutput, strconv.Itoa(d.devicePort(o))))
+	}
+
+	replies, err := d.Batch(ctx, cmds...)
+	if err != nil {
+		return HDCPStatus{}, fmt.Errorf("unable to get HDCP status: %w", err)
+	}
+
+	status := HDCPStatus{
+		Inputs:  make(map[string]bool, dims.Inputs),
+		Outputs: make(map[string]bool, dims.Outputs),
+	}
+
+	for _, reply := range replies {
+		// e.g. "~01@HDCP-STAT 0,1,1"
+		if len(reply.Params) != 3 {
+			return HDCPStatus{}, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		port, err := strconv.Atoi(reply.Params[1])
+		if err != nil {
+			return HDCPStatus{}, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		key := strconv.Itoa(d.localPort(port))
+		encrypted := reply.Params[2] == "1"
+
+		switch reply.Params[0] {
+		case _hdcpInput:
+			status.Inputs[key] = encrypted
+		case _hdcpOutput:
+			status.Outputs[key] = encrypted
+		default:
+			return HDCPStatus{}, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+	}
+
+	return status, nil
+}
+
+func (d *Device) setHDCPMode(ctx context.Context, input string, mode HDCPMode) error {
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return err
+	}
+
+	i, err := parsePort("input", input, dims.Inputs)
+	if err != nil {
+		return err
+	}
+
+	d.Log.Debugf("Setting HDCP mode on input %v to %v on %v", input, mode, d.Address)
+
+	cmd := NewCommand("HDCP-MOD", strconv.Itoa(d.devicePort(i)), strconv.Itoa(int(mode)))
+	if _, err := d.Do(ctx, cmd); err != nil {
+		return fmt.Errorf("unable to set HDCP mode: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Device) hdcpModes(ctx context.Context, inputs []string) (map[string]HDCPMode, error) {
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var cmds []Command
+	for _, input := range inputs {
+		i, err := parsePort("input", input, dims.Inputs)
+		if err != nil {
+			return nil, err
+		}
+
+		cmds = append(cmds, NewQuery("HDCP-MOD", strconv.Itoa(d.devicePort(i))))
+	}
+
+	replies, err := d.Batch(ctx, cmds...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get HDCP modes: %w", err)
+	}
+
+	toReturn := make(map[string]HDCPMode, len(inputs))
+	for i, input := range inputs {
+		reply := replies[i]
+
+		// e.g. "~01@HDCP-MOD 1,3"
+		if len(reply.Params) != 2 {
+			return nil, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		mode, err := strconv.Atoi(reply.Params[1])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		toReturn[input] = HDCPMode(mode)
+	}
+
+	return toReturn, nil
+}
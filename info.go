@@ -5,17 +5,7 @@ import (
 	"fmt"
 )
 
-//GetInfo .
-func (vs *Kramer4x4) GetInfo(ctx context.Context) (interface{}, error) {
-	return nil, fmt.Errorf("not currently implemented")
-}
-
-//GetInfo .
-func (vsdsp *KramerVP558) GetInfo(ctx context.Context) (interface{}, error) {
-	return nil, fmt.Errorf("not currently implemented")
-}
-
-//GetInfo .
-func (dsp *KramerAFM20DSP) GetInfo(ctx context.Context) (interface{}, error) {
+// GetInfo .
+func (d *Device) GetInfo(ctx context.Context) (interface{}, error) {
 	return nil, fmt.Errorf("not currently implemented")
 }
@@ -2,7 +2,9 @@ package kramer
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -14,97 +16,159 @@ import (
 func (vs *Kramer4x4) GetAudioVideoInputs(ctx context.Context) (map[string]string, error) {
 	toReturn := make(map[string]string)
 
-	for x := 0; x < 4; x++ {
-		vs.Log.Debugf("Getting input for output port %d", x)
-		vs.Log.Debugf("Changing to 1-based indexing... (+1 to each port number)")
+	routes, err := vs.readRoutes(ctx, "VID")
+	if err != nil {
+		vs.Log.Errorf("error sending command: %s", err.Error())
+		return toReturn, fmt.Errorf("error sending command: %w", err)
+	}
 
-		cmd := []byte(fmt.Sprintf("#VID? %d\r", x+1))
-		vs.Log.Debugf("Command: %s", cmd)
-		resp, err := vs.SendCommand(ctx, cmd)
-		if err != nil {
-			vs.Log.Errorf("error sending command: %s", err.Error())
-			return toReturn, fmt.Errorf("error sending command: %w", err)
+	for x, in := range routes {
+		var i status.Input
+		i.Input = fmt.Sprintf("%v:%v", in, x)
+		color.Set(color.FgGreen, color.Bold)
+		vs.Log.Debugf("Input for output port %d is %v", x, i.Input)
+
+		toReturn[strconv.Itoa(x)] = i.Input
+	}
+	return toReturn, nil
+}
+
+// readRoutes returns the (zero based) input routed to each output for a routing
+// command (VID, AUD or AV). It reads every output at once with "#VID? *", or one
+// output at a time if the device doesn't support that.
+func (vs *Kramer4x4) readRoutes(ctx context.Context, name string) (map[int]int, error) {
+	dims, err := vs.Dimensions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs []string
+
+	// the reply has a pair for every output, anything else is a notification
+	query := NewQuery(name, "*")
+	query.replyParams = dims.Outputs
+
+	reply, err := vs.Do(ctx, query)
+
+	switch {
+	case err == nil:
+		pairs = reply.Params
+	case errors.Is(err, ErrCommandNotAvailable), errors.Is(err, ErrSyntax):
+		vs.Log.Debugf("%s? * isn't supported, reading one output at a time", name)
+
+		var cmds []Command
+		for x := 0; x < dims.Outputs; x++ {
+			cmds = append(cmds, NewQuery(name, strconv.Itoa(vs.devicePort(x))))
 		}
 
-		resps := string(resp)
-		if !strings.Contains(resps, "VID") {
-			return toReturn, fmt.Errorf("Incorrect response for command (%s). (Response: %s)", cmd, resps)
+		replies, err := vs.Batch(ctx, cmds...)
+		if err != nil {
+			return nil, err
 		}
 
-		parts := strings.Split(resps, "VID")
-		resps = strings.TrimSpace(parts[1])
+		for _, reply := range replies {
+			if len(reply.Params) != 1 {
+				return nil, fmt.Errorf("Incorrect response for command (%s). (Response: %s)", name, reply)
+			}
 
-		parts = strings.Split(resps, ">")
+			pairs = append(pairs, reply.Params[0])
+		}
+	default:
+		return nil, err
+	}
 
-		var i status.Input
-		i.Input = parts[0]
+	routes := make(map[int]int)
+	for _, pair := range pairs {
+		parts := strings.Split(pair, ">")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Incorrect response for command (%s). (Response: %s)", name, pair)
+		}
 
-		vs.Log.Debugf("Changing to 0-based indexing... (-1 to each port number)")
-		i.Input, err = ToIndexZero(i.Input)
+		in, err := strconv.Atoi(parts[0])
 		if err != nil {
-			return toReturn, fmt.Errorf("unable to switch to index zero: %w", err)
+			return nil, fmt.Errorf("Incorrect response for command (%s). (Response: %s)", name, pair)
 		}
 
-		i.Input = fmt.Sprintf("%v:%v", i.Input, x)
-		color.Set(color.FgGreen, color.Bold)
-		vs.Log.Debugf("Input for output port %s is %v", i, i.Input)
+		out, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("Incorrect response for command (%s). (Response: %s)", name, pair)
+		}
 
-		toReturn[strconv.Itoa(x)] = i.Input
+		routes[vs.localPort(out)] = vs.localPort(in)
 	}
-	return toReturn, nil
+
+	for x := 0; x < dims.Outputs; x++ {
+		if _, ok := routes[x]; !ok {
+			return nil, fmt.Errorf("Incorrect response for command (%s). (No route for output %d: %v)", name, x, pairs)
+		}
+	}
+
+	return routes, nil
+}
+
+// SetRoutes routes each output in routes (output -> input) to its input with a
+// single command, so every output switches at the same time
+func (vs *Kramer4x4) SetRoutes(ctx context.Context, routes map[string]string) error {
+	return vs.setRoutes(ctx, "VID", routes)
 }
 
-// func (vs *Kramer4x4) GetAudioVideoInputs(ctx context.Context) (map[string]string, error) {
-// 	inputs := make(map[string]string)
-// 	cmd := []byte(fmt.Sprintf("#VID? *\n"))
-// 	resp, err := vs.SendCommand(ctx, cmd)
-// 	if err != nil {
-// 		return inputs, fmt.Errorf("error sending command: %w", err)
-// 	}
-// 	split := strings.Split(string(resp), "VID")
-// 	if len(split) != 2 {
-// 		// TODO weird response
-// 		return nil, fmt.Errorf("WEIRD response: %v", split)
-// 	}
-// 	for _, str := range strings.Split(split[1], ",") {
-// 		split := strings.Split(strings.TrimSpace(str), ">")
-// 		if len(split) != 2 {
-// 			// TODO weird response
-// 			return nil, fmt.Errorf("WERID Response: %v", split)
-// 		}
-// 		inputs[split[0]] = split[1]
-// 	}
-// 	return inputs, nil
-// }
+// setRoutes sends the routes for a routing command (VID, AUD or AV)
+func (vs *Kramer4x4) setRoutes(ctx context.Context, name string, routes map[string]string) error {
+	if len(routes) == 0 {
+		return nil
+	}
+
+	dims, err := vs.Dimensions(ctx)
+	if err != nil {
+		return err
+	}
+
+	outputs, inputs, err := parseRoutes(routes, dims)
+	if err != nil {
+		return err
+	}
+
+	cmd := NewCommand(name)
+	for _, o := range outputs {
+		cmd.Params = append(cmd.Params, fmt.Sprintf("%d>%d", vs.devicePort(inputs[o]), vs.devicePort(o)))
+	}
+
+	vs.Log.Debugf("Setting routes %s on %v", cmd, vs.Address)
+
+	if _, err := vs.Do(ctx, cmd); err != nil {
+		return fmt.Errorf("unable to set routes: %w", err)
+	}
+
+	return nil
+}
 
 // SwitchInput changes the input on the given output to input
 func (vs *Kramer4x4) SetAudioVideoInput(ctx context.Context, output, input string) error {
-	i, err := ToIndexOne(input)
-	if err != nil || LessThanZero(input) {
-		return fmt.Errorf("error! Input parameter %s is not valid", input)
+	dims, err := vs.Dimensions(ctx)
+	if err != nil {
+		return err
+	}
+
+	i, err := parsePort("input", input, dims.Inputs)
+	if err != nil {
+		return err
 	}
 
-	o, err := ToIndexOne(output)
-	if err != nil || LessThanZero(output) {
-		return fmt.Errorf("error! Output parameter must be zero or greater")
+	o, err := parsePort("output", output, dims.Outputs)
+	if err != nil {
+		return err
 	}
 
 	vs.Log.Debugf("Routing %v to %v on %v", input, output, vs.Address)
 	vs.Log.Debugf("Changing to 1-based indexing... (+1 to each port number)")
 
-	cmd := []byte(fmt.Sprintf("#VID %s>%s\r\n", i, o))
+	cmd := NewCommand("VID", fmt.Sprintf("%d>%d", vs.devicePort(i), vs.devicePort(o)))
 
-	resp, err := vs.SendCommand(ctx, cmd)
-	if err != nil {
+	if _, err := vs.Do(ctx, cmd); err != nil {
 		vs.Log.Errorf("unable to send command: %s", err.Error())
 		return fmt.Errorf("unable to send command: %w", err)
 	}
 
-	resps := string(resp)
-	if !strings.Contains(resps, "VID") {
-		return fmt.Errorf("Incorrect response for command (%s). (Response: %s)", cmd, resp)
-	}
-
 	return nil
 }
 
@@ -126,29 +190,37 @@ func ToIndexZero(numString string) (string, error) {
 func (vsdsp *KramerVP558) GetAudioVideoInputs(ctx context.Context) (map[string]string, error) {
 	toReturn := make(map[string]string)
 
-	for x := 0; x < 4; x++ {
-		vsdsp.Log.Debugf("Getting input for output port %s", x)
+	dims, err := vsdsp.Dimensions(ctx)
+	if err != nil {
+		return toReturn, err
+	}
 
-		cmd := []byte(fmt.Sprintf("#ROUTE? 1,%d\r\n", x))
-		resp, err := vsdsp.SendCommand(ctx, cmd, false)
-		if err != nil {
-			vsdsp.Log.Errorf("error sending command: %s", err.Error())
-			return toReturn, fmt.Errorf("error sending command: %w", err)
-		}
+	var cmds []Command
+	for x := 0; x < dims.Outputs; x++ {
+		cmds = append(cmds, NewQuery("ROUTE", "1", strconv.Itoa(vsdsp.devicePort(x))))
+	}
 
-		resps := string(resp)
-		if strings.Contains(resps, "ERR") {
-			return toReturn, fmt.Errorf("Incorrect response for command (%s). (Response: %s)", cmd, resps)
+	replies, err := vsdsp.Batch(ctx, cmds...)
+	if err != nil {
+		vsdsp.Log.Errorf("error sending command: %s", err.Error())
+		return toReturn, fmt.Errorf("error sending command: %w", err)
+	}
+
+	for x, reply := range replies {
+		cmd := cmds[x]
+		vsdsp.Log.Debugf("Getting input for output port %d", x)
+
+		if len(reply.Params) != 3 {
+			return toReturn, fmt.Errorf("Incorrect response for command (%s). (Response: %s)", cmd, reply)
 		}
-		resps = strings.TrimSpace(resps)
 
-		parts := strings.Split(resps, ",")
-		if len(parts) != 3 {
-			return toReturn, fmt.Errorf("Incorrect response for command (%s). (Response: %s)", cmd, resps)
+		in, err := strconv.Atoi(reply.Params[2])
+		if err != nil {
+			return toReturn, fmt.Errorf("Incorrect response for command (%s). (Response: %s)", cmd, reply)
 		}
 
 		var i status.Input
-		i.Input = parts[2]
+		i.Input = strconv.Itoa(vsdsp.localPort(in))
 
 		// vsdsp.Log.Infof("successfully got input", zap.String("output", output), zap.String("input", i.Input))
 		toReturn[strconv.Itoa(x)] = i.Input
@@ -159,36 +231,137 @@ func (vsdsp *KramerVP558) GetAudioVideoInputs(ctx context.Context) (map[string]s
 // SwitchInput changes the input on the given output to input
 // outputs 1-4 on device inputs 1-11 see https://cdn.kramerav.com/web/downloads/manuals/vp-558_rev_4.pdf (page 66)
 func (vsdsp *KramerVP558) SetAudioVideoInput(ctx context.Context, output, input string) error {
+	dims, err := vsdsp.Dimensions(ctx)
+	if err != nil {
+		return err
+	}
+
+	i, err := parsePort("input", input, dims.Inputs)
+	if err != nil {
+		return err
+	}
+
+	o, err := parsePort("output", output, dims.Outputs)
+	if err != nil {
+		return err
+	}
 
 	vsdsp.Log.Debugf("Routing %v to %v on %v", input, output, vsdsp.Address)
 	// vsdsp.Log.Infof("sending setInput command", zap.String("output", output), zap.String("input", input))
 
-	cmd := []byte(fmt.Sprintf("#ROUTE 1,%s,%s\r\n", output, input))
+	cmd := NewCommand("ROUTE", "1", strconv.Itoa(vsdsp.devicePort(o)), strconv.Itoa(vsdsp.devicePort(i)))
 
-	//cheack to see if the current input is going to be changing
-	currentInputs, err := vsdsp.GetAudioVideoInputs(ctx)
-	if err != nil {
-		vsdsp.Log.Errorf("error sending command: %s", err.Error())
-		return fmt.Errorf("error sending command: %w", err)
+	if _, err := vsdsp.Do(ctx, cmd); err != nil {
+		vsdsp.Log.Errorf("unable to send command: %s", err.Error())
+		return fmt.Errorf("unable to send command: %w", err)
 	}
-	//if there is a change, two responses will be sent and both need to be read
-	readAgain := false
-	if currentInputs[output] != input {
-		readAgain = true
+
+	// vsdsp.Log.Infof("successfully sent setInput command", zap.String("output", output), zap.String("input", input))
+
+	return nil
+}
+
+// SetRoutes routes each output in routes (output -> input) to its input. Every
+// route is checked before any is sent, and they are all sent in one frame.
+//
+// The VP-558 applies each route on its own, so this isn't atomic: if the device
+// rejects some of the routes, the rest are still applied, and a *PartialRoutesError
+// says which outputs weren't switched.
+func (vsdsp *KramerVP558) SetRoutes(ctx context.Context, routes map[string]string) error {
+	if len(routes) == 0 {
+		return nil
 	}
 
-	resp, err := vsdsp.SendCommand(ctx, cmd, readAgain)
+	dims, err := vsdsp.Dimensions(ctx)
 	if err != nil {
-		vsdsp.Log.Errorf("unable to send command: %s", err.Error())
-		return fmt.Errorf("unable to send command: %w", err)
+		return err
 	}
 
-	resps := string(resp)
-	if strings.Contains(resps, "ERR") {
-		return fmt.Errorf("Incorrect response for command (%s). (Response: %s)", cmd, resp)
+	outputs, inputs, err := parseRoutes(routes, dims)
+	if err != nil {
+		return err
 	}
 
-	// vsdsp.Log.Infof("successfully sent setInput command", zap.String("output", output), zap.String("input", input))
+	var cmds []Command
+	for _, o := range outputs {
+		cmds = append(cmds, NewCommand("ROUTE", "1", strconv.Itoa(vsdsp.devicePort(o)), strconv.Itoa(vsdsp.devicePort(inputs[o]))))
+	}
+
+	replies, err := vsdsp.Batch(ctx, cmds...)
+	if err != nil && len(replies) == 0 {
+		return fmt.Errorf("unable to set routes: %w", err)
+	}
+
+	perr := &PartialRoutesError{Failed: make(map[string]error)}
+	for i, reply := range replies {
+		if err := reply.Err(); err != nil {
+			perr.Failed[strconv.Itoa(outputs[i])] = err
+		}
+	}
+
+	if len(perr.Failed) > 0 {
+		return perr
+	}
 
 	return nil
 }
+
+// PartialRoutesError is returned by SetRoutes on models that apply each route
+// on their own when the device rejects some of the routes. Failed has the error
+// for each output (zero based) that wasn't switched; every other route was applied.
+type PartialRoutesError struct {
+	Failed map[string]error
+}
+
+func (e *PartialRoutesError) Error() string {
+	outputs := make([]string, 0, len(e.Failed))
+	for o := range e.Failed {
+		outputs = append(outputs, o)
+	}
+
+	sort.Strings(outputs)
+
+	failed := make([]string, len(outputs))
+	for i, o := range outputs {
+		failed[i] = fmt.Sprintf("output %s: %s", o, e.Failed[o])
+	}
+
+	return fmt.Sprintf("unable to set routes (the other routes were set): %s", strings.Join(failed, ", "))
+}
+
+// Unwrap returns the error for the lowest failed output
+func (e *PartialRoutesError) Unwrap() error {
+	var lowest string
+	for o := range e.Failed {
+		if len(lowest) == 0 || o < lowest {
+			lowest = o
+		}
+	}
+
+	return e.Failed[lowest]
+}
+
+// parseRoutes checks routes (output -> input) against dims, returning the
+// outputs in order and the input for each output
+func parseRoutes(routes map[string]string, dims Dimensions) ([]int, map[int]int, error) {
+	outputs := make([]int, 0, len(routes))
+	inputs := make(map[int]int, len(routes))
+
+	for output, input := range routes {
+		o, err := parsePort("output", output, dims.Outputs)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		i, err := parsePort("input", input, dims.Inputs)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		outputs = append(outputs, o)
+		inputs[o] = i
+	}
+
+	sort.Ints(outputs)
+	return outputs, inputs, nil
+}
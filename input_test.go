@@ -0,0 +1,101 @@
+package kramer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestReadRoutesFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		all     string
+		err     error
+		perPort int32
+	}{
+		{"all at once", "~01@VID 1>1,3>2,2>3,4>4", nil, 0},
+		{"not available", "~01@VID ERR 002", nil, 4},
+		{"syntax", "~01@ERR 001", nil, 4},
+		{"busy", "~01@VID ERR 006", ErrBusy, 0},
+		{"unauthorized", "~01@VID ERR 004", ErrUnauthorized, 0},
+	}
+
+	for _, tt := range tests {
+		var perPort int32
+		port, stop := fakeDevice(t, func(cmd string) string {
+			switch cmd {
+			case "INFO-IO?":
+				return "~01@INFO-IO IN 4,OUT 4"
+			case "VID? *":
+				return tt.all
+			case "VID? 1", "VID? 4":
+				atomic.AddInt32(&perPort, 1)
+				return "~01@VID " + cmd[5:] + ">" + cmd[5:]
+			case "VID? 2":
+				atomic.AddInt32(&perPort, 1)
+				return "~01@VID 3>2"
+			case "VID? 3":
+				atomic.AddInt32(&perPort, 1)
+				return "~01@VID 2>3"
+			default:
+				return ""
+			}
+		})
+
+		vs := NewVideoSwitcher("127.0.0.1",
+			WithTransport(TCPTransport{Address: "127.0.0.1", Port: port}),
+			WithRetryPolicy(RetryPolicy{Attempts: 1}),
+		)
+
+		routes, err := vs.readRoutes(context.Background(), "VID")
+		switch {
+		case tt.err != nil && !errors.Is(err, tt.err):
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.err)
+		case tt.err == nil && err != nil:
+			t.Errorf("%s: %s", tt.name, err)
+		case tt.err == nil && (routes[0] != 0 || routes[1] != 2 || routes[2] != 1 || routes[3] != 3):
+			t.Errorf("%s: got routes %v", tt.name, routes)
+		}
+
+		if n := atomic.LoadInt32(&perPort); n != tt.perPort {
+			t.Errorf("%s: read %d outputs one at a time, want %d", tt.name, n, tt.perPort)
+		}
+
+		stop()
+	}
+}
+
+func TestVP558PartialRoutes(t *testing.T) {
+	port, stop := fakeDevice(t, func(cmd string) string {
+		switch {
+		case cmd == "INFO-IO?":
+			return "~01@INFO-IO IN 11,OUT 4"
+		case strings.HasPrefix(cmd, "ROUTE 1,1,"):
+			return "~01@ROUTE ERR 003"
+		case strings.HasPrefix(cmd, "ROUTE "):
+			return "~01@" + cmd + " OK"
+		default:
+			return ""
+		}
+	})
+	defer stop()
+
+	vsdsp := NewVideoSwitcherDsp("127.0.0.1",
+		WithTransport(TCPTransport{Address: "127.0.0.1", Port: port}),
+		WithRetryPolicy(RetryPolicy{Attempts: 1}),
+	)
+
+	err := vsdsp.SetRoutes(context.Background(), map[string]string{"0": "1", "1": "2", "2": "3"})
+
+	var perr *PartialRoutesError
+	switch {
+	case !errors.As(err, &perr):
+		t.Fatalf("got %v, want a *PartialRoutesError", err)
+	case len(perr.Failed) != 1 || perr.Failed["1"] == nil:
+		t.Errorf("got failed outputs %v, want only output 1", perr.Failed)
+	case !errors.Is(err, ErrParameterOutOfRange):
+		t.Errorf("%v doesn't wrap the device's error", err)
+	}
+}
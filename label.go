@@ -0,0 +1,193 @@
+package kramer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrLabelNotFound is returned when no port has the given label
+var ErrLabelNotFound = errors.New("label not found")
+
+// PortLabels are the names stored on the device for each input and output (#LABEL?)
+type PortLabels struct {
+	Inputs  map[string]string
+	Outputs map[string]string
+}
+
+// LABEL takes whether the port is an input or an output
+const (
+	_labelInput  = "0"
+	_labelOutput = "1"
+)
+
+// Labels returns the label of every input and output. Labels aren't cached, so
+// changes made on the device (e.g. from its web page) show up straight away.
+func (d *Device) Labels(ctx context.Context) (PortLabels, error) {
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return PortLabels{}, err
+	}
+
+	var cmds []Command
+	for i := 0; i < dims.Inputs; i++ {
+		cmds = append(cmds, NewQuery("LABEL", _labelInput, strconv.Itoa(d.devicePort(i))))
+	}
+
+	for o := 0; o < dims.Outputs; o++ {
+		cmds = append(cmds, NewQuery("LABEL", _labelOutput, strconv.Itoa(d.devicePort(o))))
+	}
+
+	replies, err := d.Batch(ctx, cmds...)
+	if err != nil {
+		return PortLabels{}, fmt.Errorf("unable to get labels: %w", err)
+	}
+
+	labels := PortLabels{
+		Inputs:  make(map[string]string, dims.Inputs),
+		Outputs: make(map[string]string, dims.Outputs),
+	}
+
+	for _, reply := range replies {
+		// e.g. "~01@LABEL 0,1,Lectern PC"
+		if len(reply.Params) < 2 {
+			return PortLabels{}, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		port, err := strconv.Atoi(reply.Params[1])
+		if err != nil {
+			return PortLabels{}, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		key := strconv.Itoa(d.localPort(port))
+		label := strings.Join(reply.Params[2:], ",")
+
+		switch reply.Params[0] {
+		case _labelInput:
+			labels.Inputs[key] = label
+		case _labelOutput:
+			labels.Outputs[key] = label
+		default:
+			return PortLabels{}, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+	}
+
+	return labels, nil
+}
+
+// SetInputLabel stores label as the name of input on the device
+func (d *Device) SetInputLabel(ctx context.Context, input, label string) error {
+	return d.setLabel(ctx, _labelInput, input, label)
+}
+
+// SetOutputLabel stores label as the name of output on the device
+func (d *Device) SetOutputLabel(ctx context.Context, output, label string) error {
+	return d.setLabel(ctx, _labelOutput, output, label)
+}
+
+// InputByLabel returns the input labeled label. Labels are matched ignoring case.
+func (d *Device) InputByLabel(ctx context.Context, label string) (string, error) {
+	labels, err := d.Labels(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return findLabel(labels.Inputs, "input", label)
+}
+
+// OutputByLabel returns the output labeled label. Labels are matched ignoring case.
+func (d *Device) OutputByLabel(ctx context.Context, label string) (string, error) {
+	labels, err := d.Labels(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return findLabel(labels.Outputs, "output", label)
+}
+
+// SetAudioVideoInputByLabel routes the input labeled input to the output labeled output
+func (vs *Kramer4x4) SetAudioVideoInputByLabel(ctx context.Context, output, input string) error {
+	o, i, err := vs.routeByLabel(ctx, output, input)
+	if err != nil {
+		return err
+	}
+
+	return vs.SetAudioVideoInput(ctx, o, i)
+}
+
+// SetAudioVideoInputByLabel routes the input labeled input to the output labeled output
+func (vsdsp *KramerVP558) SetAudioVideoInputByLabel(ctx context.Context, output, input string) error {
+	o, i, err := vsdsp.routeByLabel(ctx, output, input)
+	if err != nil {
+		return err
+	}
+
+	return vsdsp.SetAudioVideoInput(ctx, o, i)
+}
+
+func (d *Device) setLabel(ctx context.Context, kind, port, label string) error {
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return err
+	}
+
+	name, count := "input", dims.Inputs
+	if kind == _labelOutput {
+		name, count = "output", dims.Outputs
+	}
+
+	p, err := parsePort(name, port, count)
+	if err != nil {
+		return err
+	}
+
+	d.Log.Debugf("Setting label of %s %v to %q on %v", name, port, label, d.Address)
+
+	cmd := NewCommand("LABEL", kind, strconv.Itoa(d.devicePort(p)), strings.TrimSpace(label))
+	if _, err := d.Do(ctx, cmd); err != nil {
+		return fmt.Errorf("unable to set label: %w", err)
+	}
+
+	return nil
+}
+
+// routeByLabel returns the output and input with the given labels, reading the labels once
+func (d *Device) routeByLabel(ctx context.Context, output, input string) (string, string, error) {
+	labels, err := d.Labels(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	o, err := findLabel(labels.Outputs, "output", output)
+	if err != nil {
+		return "", "", err
+	}
+
+	i, err := findLabel(labels.Inputs, "input", input)
+	if err != nil {
+		return "", "", err
+	}
+
+	return o, i, nil
+}
+
+// findLabel returns the port labeled label, or the lowest numbered one if several are
+func findLabel(labels map[string]string, kind, label string) (string, error) {
+	var ports []int
+	for port, l := range labels {
+		if strings.EqualFold(strings.TrimSpace(l), strings.TrimSpace(label)) {
+			n, _ := strconv.Atoi(port)
+			ports = append(ports, n)
+		}
+	}
+
+	if len(ports) == 0 {
+		return "", fmt.Errorf("no %s labeled %q: %w", kind, label, ErrLabelNotFound)
+	}
+
+	sort.Ints(ports)
+	return strconv.Itoa(ports[0]), nil
+}
@@ -0,0 +1,193 @@
+package kramer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Priority decides which command is sent next when several are waiting for the same device
+type Priority int
+
+const (
+	// PriorityInteractive is for commands a user is waiting on. It is the default.
+	PriorityInteractive Priority = iota
+	// PriorityBackground is for polling and monitoring, which waits for any interactive commands
+	PriorityBackground
+)
+
+type priorityKey struct{}
+
+// WithPriority returns a copy of ctx that sends any Protocol 3000 commands
+// sent with it at priority p
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// priority returns the priority set on ctx, or PriorityInteractive if there isn't one
+func priority(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+
+	return PriorityInteractive
+}
+
+// limiter queues commands for a device, letting them through one at a time in
+// priority order, at no more than rate per second (with bursts of up to burst)
+// and at least spacing apart
+type limiter struct {
+	rate    float64
+	burst   int
+	spacing time.Duration
+
+	mu       sync.Mutex
+	tokens   float64
+	refilled time.Time
+	finished time.Time
+	busy     bool
+	queue    []*waiter
+	wake     chan struct{}
+}
+
+type waiter struct {
+	priority Priority
+}
+
+func newLimiter(rate float64, burst int, spacing time.Duration) *limiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &limiter{
+		rate:     rate,
+		burst:    burst,
+		spacing:  spacing,
+		tokens:   float64(burst),
+		refilled: time.Now(),
+		wake:     make(chan struct{}),
+	}
+}
+
+// wait blocks until it is ctx's turn to send a command to the device.
+// If it returns nil, done must be called once the command is finished.
+func (l *limiter) wait(ctx context.Context) (done func(), err error) {
+	w := &waiter{priority: priority(ctx)}
+
+	l.mu.Lock()
+	l.enqueue(w)
+
+	for {
+		var delay time.Duration = -1
+		if !l.busy && l.queue[0] == w {
+			delay = l.delay(time.Now())
+			if delay <= 0 {
+				l.take(w)
+				l.mu.Unlock()
+
+				return l.done, nil
+			}
+		}
+
+		wake := l.wake
+		l.mu.Unlock()
+
+		// wait to be woken up, or (if we're next) until the delay is up
+		var timeout <-chan time.Time
+		var timer *time.Timer
+		if delay > 0 {
+			timer = time.NewTimer(delay)
+			timeout = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-wake:
+		case <-timeout:
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+
+		l.mu.Lock()
+
+		if ctx.Err() != nil {
+			l.remove(w)
+			l.broadcast()
+			l.mu.Unlock()
+
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// enqueue adds w behind everything with the same or a higher priority
+func (l *limiter) enqueue(w *waiter) {
+	i := len(l.queue)
+	for i > 0 && l.queue[i-1].priority > w.priority {
+		i--
+	}
+
+	l.queue = append(l.queue, nil)
+	copy(l.queue[i+1:], l.queue[i:])
+	l.queue[i] = w
+}
+
+func (l *limiter) remove(w *waiter) {
+	for i := range l.queue {
+		if l.queue[i] == w {
+			l.queue = append(l.queue[:i], l.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// delay returns how long to wait before the next command can be sent
+func (l *limiter) delay(now time.Time) time.Duration {
+	var delay time.Duration
+
+	if l.spacing > 0 && !l.finished.IsZero() {
+		delay = l.finished.Add(l.spacing).Sub(now)
+	}
+
+	if l.rate > 0 {
+		l.tokens += now.Sub(l.refilled).Seconds() * l.rate
+		if l.tokens > float64(l.burst) {
+			l.tokens = float64(l.burst)
+		}
+		l.refilled = now
+
+		if l.tokens < 1 {
+			if d := time.Duration((1 - l.tokens) / l.rate * float64(time.Second)); d > delay {
+				delay = d
+			}
+		}
+	}
+
+	return delay
+}
+
+func (l *limiter) take(w *waiter) {
+	l.remove(w)
+	l.busy = true
+
+	if l.rate > 0 {
+		l.tokens--
+	}
+}
+
+func (l *limiter) done() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.busy = false
+	l.finished = time.Now()
+	l.broadcast()
+}
+
+// broadcast wakes everyone waiting so the next in line can go
+func (l *limiter) broadcast() {
+	close(l.wake)
+	l.wake = make(chan struct{})
+}
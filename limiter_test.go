@@ -0,0 +1,199 @@
+package kramer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// queued waits until n commands are waiting on l
+func queued(t *testing.T, l *limiter, n int) {
+	t.Helper()
+
+	for start := time.Now(); time.Since(start) < time.Second; time.Sleep(time.Millisecond) {
+		l.mu.Lock()
+		got := len(l.queue)
+		l.mu.Unlock()
+
+		if got == n {
+			return
+		}
+	}
+
+	t.Fatalf("timed out waiting for %d queued commands", n)
+}
+
+func TestLimiterRefill(t *testing.T) {
+	l := newLimiter(10, 3, 0)
+
+	start := time.Now()
+	l.refilled = start
+
+	// the bucket starts full
+	for i := 0; i < 3; i++ {
+		if d := l.delay(start); d > 0 {
+			t.Fatalf("command %d of the burst was delayed %v", i+1, d)
+		}
+
+		l.tokens--
+	}
+
+	tests := []struct {
+		after time.Duration
+		delay time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{40 * time.Millisecond, 60 * time.Millisecond},
+		{100 * time.Millisecond, 0},
+	}
+
+	for _, tt := range tests {
+		// allow for rounding in the token count
+		if d := l.delay(start.Add(tt.after)); d < tt.delay-time.Microsecond || d > tt.delay+time.Microsecond {
+			t.Errorf("after %v: got delay %v, want %v", tt.after, d, tt.delay)
+		}
+	}
+
+	// the bucket never holds more than burst tokens
+	l.delay(start.Add(time.Minute))
+	if l.tokens != 3 {
+		t.Errorf("got %v tokens after a long idle, want 3", l.tokens)
+	}
+}
+
+func TestLimiterRate(t *testing.T) {
+	l := newLimiter(20, 2, 0)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		done, err := l.wait(context.Background())
+		if err != nil {
+			t.Fatalf("wait: %s", err)
+		}
+
+		done()
+	}
+
+	// two commands in the burst, then two more at 20 per second
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("4 commands took %v, want at least 100ms", elapsed)
+	}
+}
+
+func TestLimiterSpacing(t *testing.T) {
+	l := newLimiter(0, 0, 50*time.Millisecond)
+
+	start := time.Now()
+	if d := l.delay(start); d > 0 {
+		t.Fatalf("first command was delayed %v", d)
+	}
+
+	l.finished = start
+	if d := l.delay(start.Add(20 * time.Millisecond)); d != 30*time.Millisecond {
+		t.Errorf("got delay %v, want 30ms", d)
+	}
+
+	done, err := l.wait(context.Background())
+	if err != nil {
+		t.Fatalf("wait: %s", err)
+	}
+
+	done()
+	finished := time.Now()
+
+	done, err = l.wait(context.Background())
+	if err != nil {
+		t.Fatalf("wait: %s", err)
+	}
+
+	done()
+
+	if gap := time.Since(finished); gap < 50*time.Millisecond {
+		t.Errorf("commands were %v apart, want at least 50ms", gap)
+	}
+}
+
+func TestLimiterPriority(t *testing.T) {
+	l := newLimiter(0, 0, 0)
+
+	// hold the device so the others have to queue
+	done, err := l.wait(context.Background())
+	if err != nil {
+		t.Fatalf("wait: %s", err)
+	}
+
+	order := make(chan Priority, 3)
+	send := func(p Priority) {
+		done, err := l.wait(WithPriority(context.Background(), p))
+		if err != nil {
+			t.Errorf("wait: %s", err)
+			return
+		}
+
+		order <- p
+		done()
+	}
+
+	go send(PriorityBackground)
+	queued(t, l, 1)
+
+	go send(PriorityBackground)
+	queued(t, l, 2)
+
+	go send(PriorityInteractive)
+	queued(t, l, 3)
+
+	done()
+
+	want := []Priority{PriorityInteractive, PriorityBackground, PriorityBackground}
+	for i := range want {
+		select {
+		case got := <-order:
+			if got != want[i] {
+				t.Errorf("command %d had priority %v, want %v", i+1, got, want[i])
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for command %d", i+1)
+		}
+	}
+}
+
+func TestLimiterCancel(t *testing.T) {
+	l := newLimiter(0, 0, 0)
+
+	done, err := l.wait(context.Background())
+	if err != nil {
+		t.Fatalf("wait: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := l.wait(ctx)
+		errs <- err
+	}()
+
+	queued(t, l, 1)
+	cancel()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("cancelled wait didn't return")
+	}
+
+	queued(t, l, 0)
+	done()
+
+	// the cancelled command doesn't hold up the next one
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := l.wait(ctx); err != nil {
+		t.Errorf("wait after a cancelled command: %s", err)
+	}
+}
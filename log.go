@@ -30,3 +30,23 @@ func (v *Via) Errorf(format string, a ...interface{}) {
 		v.Logger.Errorf(format, a...)
 	}
 }
+
+func debugf(l Logger, format string, a ...interface{}) {
+	if l != nil {
+		l.Debugf(format, a...)
+	}
+}
+
+func warnf(l Logger, format string, a ...interface{}) {
+	if l != nil {
+		l.Warnf(format, a...)
+	}
+}
+
+// nopLogger is used when no logger is given
+type nopLogger struct{}
+
+func (nopLogger) Debugf(format string, a ...interface{}) {}
+func (nopLogger) Infof(format string, a ...interface{})  {}
+func (nopLogger) Warnf(format string, a ...interface{})  {}
+func (nopLogger) Errorf(format string, a ...interface{}) {}
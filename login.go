@@ -0,0 +1,41 @@
+package kramer
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLoginFailed is returned when a device with security enabled rejects the
+// credentials it was given. The underlying *Error (usually ErrUnauthorized)
+// can be retrieved with errors.As.
+var ErrLoginFailed = errors.New("login failed")
+
+type loginError struct {
+	err error
+}
+
+func (e *loginError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrLoginFailed, e.err)
+}
+
+func (e *loginError) Unwrap() error {
+	return e.err
+}
+
+func (e *loginError) Is(target error) bool {
+	return target == ErrLoginFailed
+}
+
+// login sends #LOGIN on a freshly opened connection
+func login(conn *bufferedConn, username, password string) error {
+	reply, err := roundTrip(conn, NewCommand("LOGIN", username, password))
+	if err != nil {
+		return fmt.Errorf("unable to login: %w", err)
+	}
+
+	if err := reply.Err(); err != nil {
+		return &loginError{err}
+	}
+
+	return nil
+}
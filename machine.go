@@ -0,0 +1,22 @@
+package kramer
+
+import "context"
+
+type machineKey struct{}
+
+// WithMachine returns a copy of ctx that addresses any Protocol 3000 commands
+// sent with it to machine number n (i.e. "#n@CMD"), overriding the machine
+// number the driver was created with. This is used to control daisy-chained
+// (RS-485) devices that sit behind a single networked device.
+func WithMachine(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, machineKey{}, n)
+}
+
+// machineNumber returns the machine number set on ctx, or def if there isn't one
+func machineNumber(ctx context.Context, def int) int {
+	if n, ok := ctx.Value(machineKey{}).(int); ok {
+		return n
+	}
+
+	return def
+}
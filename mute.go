@@ -3,7 +3,6 @@ package kramer
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"go.uber.org/zap"
 )
@@ -14,24 +13,26 @@ func (dsp *KramerAFM20DSP) Mutes(ctx context.Context, blocks []string) (map[stri
 
 	toReturn := make(map[string]bool)
 
+	var cmds []Command
 	for _, block := range blocks {
 		dsp.Log.Infof("sending get muteStatus command", zap.String("block", block))
-		cmd := []byte(fmt.Sprintf("#X-MUTE? OUT.ANALOG_AUDIO.%s.AUDIO.1\r\n", block))
-		resp, err := dsp.SendCommand(ctx, cmd)
-		if err != nil {
-			dsp.Log.Errorf("error sending command: %s", err.Error())
-			return toReturn, fmt.Errorf("error sending command: %w", err)
-		}
+		cmds = append(cmds, NewQuery("X-MUTE", fmt.Sprintf("OUT.ANALOG_AUDIO.%s.AUDIO.1", block)))
+	}
 
-		resps := string(resp)
-		if strings.Contains(resps, "ERR") {
-			return toReturn, fmt.Errorf("an error occured: (command: %s) response: %s)", cmd, resps)
-		}
-		resps = strings.TrimSpace(resps)
+	replies, err := dsp.Batch(ctx, cmds...)
+	if err != nil {
+		dsp.Log.Errorf("error sending command: %s", err.Error())
+		return toReturn, fmt.Errorf("error sending command: %w", err)
+	}
+
+	for i, block := range blocks {
+		reply := replies[i]
 
-		parts := strings.Split(resps, ",")
+		if len(reply.Params) != 2 {
+			return toReturn, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
 
-		if parts[1] == "OFF" {
+		if reply.Params[1] == "OFF" {
 			dsp.Log.Infof("successfully got mute status", zap.String("block", block), zap.Bool("status", false))
 			toReturn[block] = false
 		} else {
@@ -48,23 +49,17 @@ func (dsp *KramerAFM20DSP) Mutes(ctx context.Context, blocks []string) (map[stri
 func (dsp *KramerAFM20DSP) SetMute(ctx context.Context, block string, mute bool) error {
 	dsp.Log.Infof("sending set muteStatus command", zap.String("block", block), zap.Bool("status", mute))
 
-	var cmd []byte
+	state := "OFF"
 	if mute {
-		cmd = []byte(fmt.Sprintf("#X-MUTE OUT.ANALOG_AUDIO.%s.AUDIO.1, ON\r", block))
-	} else {
-		cmd = []byte(fmt.Sprintf("#X-MUTE OUT.ANALOG_AUDIO.%s.AUDIO.1, OFF\r", block))
+		state = "ON"
 	}
-	resp, err := dsp.SendCommand(ctx, cmd)
-	if err != nil {
+
+	cmd := NewCommand("X-MUTE", fmt.Sprintf("OUT.ANALOG_AUDIO.%s.AUDIO.1", block), state)
+	if _, err := dsp.Do(ctx, cmd); err != nil {
 		dsp.Log.Errorf("error sending command: %s", err.Error())
 		return fmt.Errorf("error sending command: %w", err)
 	}
 
-	resps := string(resp)
-	if strings.Contains(resps, "ERR") {
-		return fmt.Errorf("an error occured: (command: %s) response: %s)", cmd, resps)
-	}
-
 	dsp.Log.Infof("successfully set mute status", zap.String("block", block), zap.Bool("status", mute))
 
 	return nil
@@ -76,28 +71,26 @@ func (dsp *KramerAFM20DSP) SetMute(ctx context.Context, block string, mute bool)
 func (vsdsp *KramerVP558) Mutes(ctx context.Context, blocks []string) (map[string]bool, error) {
 	toReturn := make(map[string]bool)
 
+	var cmds []Command
 	for _, block := range blocks {
 		vsdsp.Log.Infof("sending get mute status command", zap.String("block", block))
-		cmd := []byte(fmt.Sprintf("#MUTE? %s\r\n", block))
-		resp, err := vsdsp.SendCommand(ctx, cmd, false)
-		if err != nil {
-			vsdsp.Log.Errorf("error sending command: %s", err.Error())
-			return toReturn, fmt.Errorf("error sending command: %w", err)
-		}
+		cmds = append(cmds, NewQuery("MUTE", block))
+	}
 
-		resps := string(resp)
-		if strings.Contains(resps, "ERR") {
-			return toReturn, fmt.Errorf("an error occured: (command: %s) response: %s)", cmd, resps)
-		}
-		resps = strings.TrimSpace(resps)
+	replies, err := vsdsp.Batch(ctx, cmds...)
+	if err != nil {
+		vsdsp.Log.Errorf("error sending command: %s", err.Error())
+		return toReturn, fmt.Errorf("error sending command: %w", err)
+	}
 
-		parts := strings.Split(resps, ",")
+	for i, block := range blocks {
+		reply := replies[i]
 
-		if len(parts) < 2 {
-			return toReturn, fmt.Errorf("unexpected response, unable to parse: %s", resps)
+		if len(reply.Params) < 2 {
+			return toReturn, fmt.Errorf("unexpected response, unable to parse: %s", reply)
 		}
 
-		if parts[1] == "0" {
+		if reply.Params[1] == "0" {
 			vsdsp.Log.Infof("successfully got mute status", zap.String("block", block), zap.Bool("status", false))
 			toReturn[block] = false
 		} else {
@@ -115,35 +108,17 @@ func (vsdsp *KramerVP558) Mutes(ctx context.Context, blocks []string) (map[strin
 func (vsdsp *KramerVP558) SetMute(ctx context.Context, block string, muted bool) error {
 	vsdsp.Log.Infof("sending set muteStatus command", zap.String("block", block), zap.Bool("status", muted))
 
-	//cheack to see if the mute status is going to be changing
-	currentStatus, err := vsdsp.Mutes(ctx, []string{block})
-	if err != nil {
-		vsdsp.Log.Errorf("error sending command: %s", err.Error())
-		return fmt.Errorf("error sending command: %w", err)
-	}
-	//if there is a change, two responses will be sent and both need to be read
-	readAgain := false
-	if currentStatus[block] != muted {
-		readAgain = true
-	}
-
-	var cmd []byte
+	state := "0"
 	if muted {
-		cmd = []byte(fmt.Sprintf("#MUTE %s,1\r", block))
-	} else {
-		cmd = []byte(fmt.Sprintf("#MUTE %s,0\r", block))
+		state = "1"
 	}
-	resp, err := vsdsp.SendCommand(ctx, cmd, readAgain)
-	if err != nil {
+
+	cmd := NewCommand("MUTE", block, state)
+	if _, err := vsdsp.Do(ctx, cmd); err != nil {
 		vsdsp.Log.Errorf("error sending command: %s", err.Error())
 		return fmt.Errorf("error sending command: %w", err)
 	}
 
-	resps := string(resp)
-	if strings.Contains(resps, "ERR") {
-		return fmt.Errorf("an error occured: (command: %s) response: %s)", cmd, resps)
-	}
-
 	vsdsp.Log.Infof("successfully set mute status", zap.String("block", block), zap.Bool("status", muted))
 
 	return nil
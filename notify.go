@@ -0,0 +1,246 @@
+package kramer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NotificationKind is the type of change a device is reporting
+type NotificationKind int
+
+const (
+	// OtherChanged is any notification not covered by another kind
+	OtherChanged NotificationKind = iota
+	RouteChanged
+	SignalChanged
+	VolumeChanged
+	MuteChanged
+	VideoMuteChanged
+)
+
+var notificationKinds = map[string]NotificationKind{
+	"VID":       RouteChanged,
+	"AUD":       RouteChanged,
+	"AV":        RouteChanged,
+	"ROUTE":     RouteChanged,
+	"PRST-RCL":  RouteChanged,
+	"SIGNAL":    SignalChanged,
+	"DISPLAY":   SignalChanged,
+	"AUD-LVL":   VolumeChanged,
+	"X-AUD-LVL": VolumeChanged,
+	"MUTE":      MuteChanged,
+	"X-MUTE":    MuteChanged,
+	"VMUTE":     VideoMuteChanged,
+	"FREEZE":    VideoMuteChanged,
+}
+
+func (k NotificationKind) String() string {
+	switch k {
+	case RouteChanged:
+		return "route"
+	case SignalChanged:
+		return "signal"
+	case VolumeChanged:
+		return "volume"
+	case MuteChanged:
+		return "mute"
+	case VideoMuteChanged:
+		return "video mute"
+	default:
+		return "other"
+	}
+}
+
+// Notification is an unsolicited change notification sent by a device,
+// e.g. "~01@VID 1>2" after someone changes a route from the front panel.
+type Notification struct {
+	Kind  NotificationKind
+	Reply Reply
+}
+
+const (
+	_notificationBuffer    = 16
+	_listenerRetryInterval = 5 * time.Second
+	_listenerKeepAlive     = 30 * time.Second
+)
+
+// notifier keeps a dedicated connection open to the device while anyone is
+// subscribed, and fans the notifications it reads out to the subscribers.
+// If passive is set (for transports that only allow one connection at a time)
+// no listener connection is opened and only notifications read while
+// waiting for command replies are sent to subscribers.
+type notifier struct {
+	dial    func(context.Context) (net.Conn, error)
+	log     Logger
+	passive bool
+
+	mu     sync.Mutex
+	subs   map[*subscription]struct{}
+	cancel context.CancelFunc
+}
+
+type subscription struct {
+	kinds map[NotificationKind]bool
+	ch    chan Notification
+}
+
+func newNotifier(dial func(context.Context) (net.Conn, error), log Logger, passive bool) *notifier {
+	return &notifier{
+		dial:    dial,
+		log:     log,
+		passive: passive,
+		subs:    make(map[*subscription]struct{}),
+	}
+}
+
+// subscribe returns a channel of notifications of the given kinds (or all
+// notifications if none are given). The channel is closed when ctx is done.
+func (n *notifier) subscribe(ctx context.Context, kinds []NotificationKind) <-chan Notification {
+	sub := &subscription{
+		kinds: make(map[NotificationKind]bool),
+		ch:    make(chan Notification, _notificationBuffer),
+	}
+
+	for _, k := range kinds {
+		sub.kinds[k] = true
+	}
+
+	n.mu.Lock()
+	n.subs[sub] = struct{}{}
+
+	if n.cancel == nil && !n.passive {
+		lctx, cancel := context.WithCancel(context.Background())
+		n.cancel = cancel
+
+		go n.listen(lctx)
+	}
+	n.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		delete(n.subs, sub)
+		close(sub.ch)
+
+		if len(n.subs) == 0 && n.cancel != nil {
+			n.cancel()
+			n.cancel = nil
+		}
+	}()
+
+	return sub.ch
+}
+
+// skipped is called with each notification read while waiting for a command reply
+func (n *notifier) skipped(reply Reply) {
+	if !n.passive {
+		debugf(n.log, "skipping notification while waiting for reply: %s", reply)
+		return
+	}
+
+	n.publish(reply)
+}
+
+func (n *notifier) publish(reply Reply) {
+	notif := Notification{
+		Kind:  notificationKinds[strings.ToUpper(reply.Command)],
+		Reply: reply,
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for sub := range n.subs {
+		if len(sub.kinds) > 0 && !sub.kinds[notif.Kind] {
+			continue
+		}
+
+		select {
+		case sub.ch <- notif:
+		default:
+			warnf(n.log, "dropping notification for slow subscriber: %s", reply)
+		}
+	}
+}
+
+func (n *notifier) listen(ctx context.Context) {
+	for {
+		err := n.listenOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		warnf(n.log, "notification listener disconnected: %s", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(_listenerRetryInterval):
+		}
+	}
+}
+
+func (n *notifier) listenOnce(ctx context.Context) error {
+	conn, err := n.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	// close the connection to stop reading once we're done, and send
+	// an empty command every so often so the device keeps it open
+	go func() {
+		ticker := time.NewTicker(_listenerKeepAlive)
+		defer ticker.Stop()
+		defer conn.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+				if _, err := conn.Write([]byte{'#', CARRIAGE_RETURN}); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadBytes(LINE_FEED)
+		if err != nil {
+			return err
+		}
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		reply, err := ParseReply(line)
+		if err != nil {
+			debugf(n.log, "notification listener skipping unparsable line: %s", err)
+			continue
+		}
+
+		// anything with a status is a reply (e.g. to our keep alive)
+		if reply.Status != StatusNone {
+			continue
+		}
+
+		n.publish(reply)
+	}
+}
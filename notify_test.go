@@ -0,0 +1,88 @@
+package kramer
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSubscribe(t *testing.T) {
+	client, device := net.Pipe()
+	defer device.Close()
+
+	dialed := make(chan struct{}, 1)
+	n := newNotifier(func(context.Context) (net.Conn, error) {
+		dialed <- struct{}{}
+		return client, nil
+	}, nil, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := n.subscribe(ctx, []NotificationKind{RouteChanged})
+
+	select {
+	case <-dialed:
+	case <-time.After(time.Second):
+		t.Fatalf("listener never connected")
+	}
+
+	// the reply to a keep alive and notifications of other kinds aren't sent to the subscriber
+	go device.Write([]byte("~01@ OK\r\n~01@AUD-LVL 1,50\r\n~01@VID 1>2\r\n"))
+
+	select {
+	case notif := <-ch:
+		if notif.Kind != RouteChanged || notif.Reply.String() != "~01@VID 1>2" {
+			t.Errorf("got notification %+v", notif)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no notification")
+	}
+
+	cancel()
+
+	select {
+	case notif, ok := <-ch:
+		if ok {
+			t.Fatalf("got notification %+v after cancelling", notif)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("channel wasn't closed after cancelling")
+	}
+
+	// the listener closes its connection once nobody is subscribed
+	_ = device.SetReadDeadline(time.Now().Add(time.Second))
+	_, err := device.Read(make([]byte, 1))
+	if nerr, ok := err.(net.Error); err == nil || ok && nerr.Timeout() {
+		t.Errorf("listener connection is still open")
+	}
+}
+
+func TestSubscribePassive(t *testing.T) {
+	n := newNotifier(func(context.Context) (net.Conn, error) {
+		t.Errorf("passive notifier opened a connection")
+		return nil, nil
+	}, nil, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := n.subscribe(ctx, nil)
+
+	reply, err := ParseReply([]byte("~01@SIGNAL 1,1\r\n"))
+	if err != nil {
+		t.Fatalf("ParseReply: %s", err)
+	}
+
+	n.skipped(reply)
+
+	select {
+	case notif := <-ch:
+		if notif.Kind != SignalChanged {
+			t.Errorf("got kind %s, want %s", notif.Kind, SignalChanged)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("notification read while waiting for a reply wasn't sent to the subscriber")
+	}
+}
@@ -0,0 +1,127 @@
+package kramer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/byuoitav/common/structs"
+)
+
+// Driver is a Protocol 3000 device returned by Open. Use a type switch to get
+// the model specific driver (*Kramer4x4, *KramerVP558, *KramerAFM20DSP or *Device).
+type Driver interface {
+	Profile() Profile
+	Do(ctx context.Context, cmd Command) (Reply, error)
+	Batch(ctx context.Context, cmds ...Command) ([]Reply, error)
+	Subscribe(ctx context.Context, kinds ...NotificationKind) <-chan Notification
+	GetHardwareInfo(ctx context.Context) (structs.HardwareInfo, error)
+	GetInfo(ctx context.Context) (interface{}, error)
+}
+
+// ErrUnknownModel is returned (wrapped in an *UnknownModelError) by Open if
+// the device reports a model without a registered profile
+var ErrUnknownModel = errors.New("unknown model")
+
+// UnknownModelError is returned by Open if the device reports a model without
+// a registered profile. Use NewDevice to control it anyways.
+type UnknownModelError struct {
+	Model           string
+	ProtocolVersion string
+}
+
+func (e *UnknownModelError) Error() string {
+	return fmt.Sprintf("unknown model %q (protocol %s)", e.Model, e.ProtocolVersion)
+}
+
+func (e *UnknownModelError) Unwrap() error {
+	return ErrUnknownModel
+}
+
+type registeredProfile struct {
+	profile Profile
+	wrap    func(*Device) Driver
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = []registeredProfile{
+		{profile: ProfileVS44, wrap: func(d *Device) Driver { return &Kramer4x4{Device: d} }},
+		{profile: ProfileVP558, wrap: func(d *Device) Driver { return &KramerVP558{Device: d} }},
+		{profile: ProfileAFM20DSP, wrap: func(d *Device) Driver { return &KramerAFM20DSP{Device: d} }},
+	}
+)
+
+// RegisterProfile lets Open recognize another model. Open returns a *Device
+// for devices that match p. Registering a profile for a model that already
+// has one (e.g. ProfileVS44 with different port counts) replaces it.
+func RegisterProfile(p Profile) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for i := range registry {
+		if strings.EqualFold(registry[i].profile.Model, p.Model) {
+			registry[i].profile = p
+			return
+		}
+	}
+
+	registry = append(registry, registeredProfile{profile: p})
+}
+
+// lookupProfile returns the registered profile whose model is the longest prefix of model
+func lookupProfile(model string) (registeredProfile, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	var match registeredProfile
+	var found bool
+
+	model = strings.ToUpper(model)
+	for _, r := range registry {
+		prefix := strings.ToUpper(r.profile.Model)
+		if len(prefix) == 0 || !strings.HasPrefix(model, prefix) {
+			continue
+		}
+
+		if !found || len(prefix) > len(match.profile.Model) {
+			match, found = r, true
+		}
+	}
+
+	return match, found
+}
+
+// Open asks the device at addr for its model (#MODEL?) and protocol version
+// (#PROT-VER?) and returns the matching driver. It returns an *UnknownModelError
+// if no profile has been registered for the model.
+func Open(ctx context.Context, addr string, opts ...Option) (Driver, error) {
+	d := NewDevice(addr, Profile{}, opts...)
+
+	replies, err := d.Batch(ctx, NewQuery(Model), NewQuery(ProtocolVersion))
+	if err != nil {
+		return nil, fmt.Errorf("unable to identify device at %s: %w", addr, err)
+	}
+
+	model := replies[0].Value()
+	version := strings.TrimPrefix(replies[1].Value(), "3000:")
+	if !strings.HasPrefix(replies[1].Value(), "3000") {
+		return nil, fmt.Errorf("device at %s doesn't speak Protocol 3000 (protocol version %q)", addr, replies[1].Value())
+	}
+
+	r, ok := lookupProfile(model)
+	if !ok {
+		return nil, &UnknownModelError{Model: model, ProtocolVersion: version}
+	}
+
+	d.Log.Debugf("%s is a %s, using the %s profile", addr, model, r.profile.Model)
+	d.setProfile(r.profile)
+
+	if r.wrap == nil {
+		return d, nil
+	}
+
+	return r.wrap(d), nil
+}
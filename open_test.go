@@ -0,0 +1,163 @@
+package kramer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLookupProfile(t *testing.T) {
+	registryMu.Lock()
+	saved := append([]registeredProfile(nil), registry...)
+	registryMu.Unlock()
+
+	defer func() {
+		registryMu.Lock()
+		registry = saved
+		registryMu.Unlock()
+	}()
+
+	RegisterProfile(Profile{Model: "VS-44H2", Inputs: 4, Outputs: 4, IndexBase: 1})
+	RegisterProfile(Profile{Model: "VP-558", Inputs: 9, Outputs: 2})
+
+	tests := []struct {
+		model   string
+		profile string
+		inputs  int
+		found   bool
+	}{
+		{"VS-44", "VS-44", 4, true},
+		{"VS-44DT", "VS-44", 4, true},
+		{"vs-44dt", "VS-44", 4, true},
+		{"VS-44H2", "VS-44H2", 4, true},
+		{"VP-558", "VP-558", 9, true},
+		{"AFM-20DSP-AEC", "AFM-20DSP", 0, true},
+		{"VS-4", "", 0, false},
+		{"FC-28", "", 0, false},
+		{"", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		r, found := lookupProfile(tt.model)
+		switch {
+		case found != tt.found:
+			t.Errorf("lookupProfile(%q): found = %v, want %v", tt.model, found, tt.found)
+		case found && (r.profile.Model != tt.profile || r.profile.Inputs != tt.inputs):
+			t.Errorf("lookupProfile(%q) = %s with %d inputs, want %s with %d inputs", tt.model, r.profile.Model, r.profile.Inputs, tt.profile, tt.inputs)
+		}
+	}
+
+	// replacing a built in profile keeps its driver
+	if r, _ := lookupProfile("VP-558"); r.wrap == nil {
+		t.Errorf("replacing the VP-558 profile lost its driver")
+	}
+}
+
+// fakeDevice is a Protocol 3000 device on TCP that answers each command
+// (e.g. "MODEL?") with the line returned by answer. The handshake and any
+// command answer doesn't know are answered with "~01@ OK". It returns the
+// port it listens on.
+func fakeDevice(t *testing.T, answer func(cmd string) string) (int, func()) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(c net.Conn) {
+				defer c.Close()
+
+				r := bufio.NewReader(c)
+				for {
+					line, err := r.ReadString(CARRIAGE_RETURN)
+					if err != nil {
+						return
+					}
+
+					for _, cmd := range strings.Split(strings.TrimPrefix(strings.TrimSpace(line), "#"), "|") {
+						reply := answer(cmd)
+						if len(reply) == 0 {
+							reply = "~01@ OK"
+						}
+
+						c.Write([]byte(reply + "\r\n"))
+					}
+				}
+			}(c)
+		}
+	}()
+
+	return ln.Addr().(*net.TCPAddr).Port, func() { ln.Close() }
+}
+
+// fakeModelDevice answers #MODEL? and #PROT-VER? like a device of the given
+// model. It returns the port it listens on.
+func fakeModelDevice(t *testing.T, model string) (int, func()) {
+	return fakeDevice(t, func(cmd string) string {
+		switch cmd {
+		case "MODEL?":
+			return "~01@MODEL " + model
+		case "PROT-VER?":
+			return "~01@PROT-VER 3000:1.0"
+		default:
+			return ""
+		}
+	})
+}
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		model string
+		check func(Driver) bool
+	}{
+		{"VS-44DT", func(d Driver) bool { _, ok := d.(*Kramer4x4); return ok }},
+		{"VP-558", func(d Driver) bool { _, ok := d.(*KramerVP558); return ok }},
+		{"AFM-20DSP", func(d Driver) bool { _, ok := d.(*KramerAFM20DSP); return ok }},
+	}
+
+	for _, tt := range tests {
+		port, stop := fakeModelDevice(t, tt.model)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		d, err := Open(ctx, "127.0.0.1", WithTransport(TCPTransport{Address: "127.0.0.1", Port: port}))
+		cancel()
+		stop()
+
+		switch {
+		case err != nil:
+			t.Errorf("Open(%s): %s", tt.model, err)
+		case !tt.check(d):
+			t.Errorf("Open(%s) returned a %T", tt.model, d)
+		}
+	}
+}
+
+func TestOpenUnknownModel(t *testing.T) {
+	port, stop := fakeModelDevice(t, "FC-28")
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := Open(ctx, "127.0.0.1", WithTransport(TCPTransport{Address: "127.0.0.1", Port: port}))
+
+	var merr *UnknownModelError
+	switch {
+	case !errors.As(err, &merr):
+		t.Fatalf("got %v, want an *UnknownModelError", err)
+	case merr.Model != "FC-28" || merr.ProtocolVersion != "1.0":
+		t.Errorf("got %+v", merr)
+	case !errors.Is(err, ErrUnknownModel):
+		t.Errorf("%v doesn't wrap ErrUnknownModel", err)
+	}
+}
@@ -0,0 +1,138 @@
+package kramer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SavePreset stores the current routing as preset
+func (vs *Kramer4x4) SavePreset(ctx context.Context, preset int) error {
+	return vs.savePreset(ctx, preset)
+}
+
+// RecallPreset switches every output to the routing stored in preset
+func (vs *Kramer4x4) RecallPreset(ctx context.Context, preset int) error {
+	return vs.recallPreset(ctx, preset)
+}
+
+// Presets returns the numbers of the stored presets
+func (vs *Kramer4x4) Presets(ctx context.Context) ([]int, error) {
+	return vs.presets(ctx)
+}
+
+// PresetRoutes returns the video input routed to each output (output -> input) in preset
+func (vs *Kramer4x4) PresetRoutes(ctx context.Context, preset int) (map[string]string, error) {
+	return vs.presetRoutes(ctx, preset)
+}
+
+// SavePreset stores the current routing as preset
+func (vsdsp *KramerVP558) SavePreset(ctx context.Context, preset int) error {
+	return vsdsp.savePreset(ctx, preset)
+}
+
+// RecallPreset switches every output to the routing stored in preset
+func (vsdsp *KramerVP558) RecallPreset(ctx context.Context, preset int) error {
+	return vsdsp.recallPreset(ctx, preset)
+}
+
+// Presets returns the numbers of the stored presets
+func (vsdsp *KramerVP558) Presets(ctx context.Context) ([]int, error) {
+	return vsdsp.presets(ctx)
+}
+
+// PresetRoutes returns the video input routed to each output (output -> input) in preset
+func (vsdsp *KramerVP558) PresetRoutes(ctx context.Context, preset int) (map[string]string, error) {
+	return vsdsp.presetRoutes(ctx, preset)
+}
+
+// savePreset stores the current routing with #PRST-STO, or #PRESET on devices
+// that don't support #PRST-STO
+func (d *Device) savePreset(ctx context.Context, preset int) error {
+	p := strconv.Itoa(preset)
+
+	_, err := d.Do(ctx, NewCommand("PRST-STO", p))
+	if errors.Is(err, ErrCommandNotAvailable) {
+		_, err = d.Do(ctx, NewCommand("PRESET", p))
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to save preset %d: %w", preset, err)
+	}
+
+	return nil
+}
+
+func (d *Device) recallPreset(ctx context.Context, preset int) error {
+	if _, err := d.Do(ctx, NewCommand("PRST-RCL", strconv.Itoa(preset))); err != nil {
+		return fmt.Errorf("unable to recall preset %d: %w", preset, err)
+	}
+
+	return nil
+}
+
+// presets parses the reply to #PRST-LST?, e.g. "~01@PRST-LST 1,2,5"
+func (d *Device) presets(ctx context.Context) ([]int, error) {
+	reply, err := d.Do(ctx, NewQuery("PRST-LST"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list presets: %w", err)
+	}
+
+	var presets []int
+	for _, p := range reply.Params {
+		for _, field := range strings.Fields(p) {
+			n, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("unexpected response to PRST-LST: %s", reply)
+			}
+
+			presets = append(presets, n)
+		}
+	}
+
+	return presets, nil
+}
+
+// presetRoutes reads the video routing of preset one output at a time with #PRST-VID?
+func (d *Device) presetRoutes(ctx context.Context, preset int) (map[string]string, error) {
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p := strconv.Itoa(preset)
+
+	var cmds []Command
+	for x := 0; x < dims.Outputs; x++ {
+		cmds = append(cmds, NewQuery("PRST-VID", p, strconv.Itoa(d.devicePort(x))))
+	}
+
+	replies, err := d.Batch(ctx, cmds...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get routes of preset %d: %w", preset, err)
+	}
+
+	routes := make(map[string]string, len(replies))
+	for x, reply := range replies {
+		// e.g. "~01@PRST-VID 3,2>1"
+		if len(reply.Params) != 2 {
+			return nil, fmt.Errorf("unexpected response to PRST-VID: %s", reply)
+		}
+
+		parts := strings.Split(reply.Params[1], ">")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("unexpected response to PRST-VID: %s", reply)
+		}
+
+		in, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected response to PRST-VID: %s", reply)
+		}
+
+		routes[strconv.Itoa(x)] = strconv.Itoa(d.localPort(in))
+	}
+
+	return routes, nil
+}
@@ -0,0 +1,94 @@
+package kramer
+
+import "strings"
+
+// Profile describes what a Kramer model supports. Adding support for a
+// new model should only mean adding a profile.
+type Profile struct {
+	// Model is the model name, as reported by #MODEL? (e.g. "VS-44DT").
+	// Open matches it against the start of the reported model, so "VS-44"
+	// matches both the VS-44DT and the VS-44H2.
+	Model string
+
+	// Inputs and Outputs are the number of video inputs and outputs
+	Inputs  int
+	Outputs int
+
+	// IndexBase is the number the device uses for its first port. Ports are
+	// always zero based in this package, and are converted for the device.
+	IndexBase int
+
+	// Commands are the commands the model supports, besides the ones every
+	// Protocol 3000 device supports (MODEL?, LOGIN...). Every command is
+	// allowed if Commands is empty.
+	Commands []string
+
+	// Notifies is set if the model sends change notifications (e.g. "~01@VID 1>2")
+	// when its state is changed from somewhere else
+	Notifies bool
+}
+
+// Built in profiles
+var (
+	ProfileVS44 = Profile{
+		Model:     "VS-44",
+		Inputs:    4,
+		Outputs:   4,
+		IndexBase: 1,
+		Commands: append([]string{
+			"VID", "AUD", "AV", "LOCK-FP", "AV-SW-MODE", "PRIORITY",
+		}, switcherCommands...),
+		Notifies: true,
+	}
+
+	ProfileVP558 = Profile{
+		Model:     "VP-558",
+		Inputs:    11,
+		Outputs:   4,
+		IndexBase: 0,
+		Commands: append([]string{
+			"ROUTE", "MUTE", "AUD-LVL", "AV-SW-MODE", "PRIORITY",
+		}, switcherCommands...),
+		Notifies: true,
+	}
+
+	ProfileAFM20DSP = Profile{
+		Model:     "AFM-20DSP",
+		IndexBase: 1,
+		Commands:  []string{"X-MUTE", "X-AUD-LVL", "INFO-IO"},
+		Notifies:  true,
+	}
+)
+
+// switcherCommands are supported by both the VS-44 and the VP-558
+var switcherCommands = []string{
+	"INFO-IO", "LABEL", Signal, "DISPLAY",
+	"PRST-STO", "PRESET", "PRST-RCL", "PRST-LST", "PRST-VID",
+	"VMUTE", "FREEZE",
+	"CPEDID", "LOCK-EDID", "EDID-SRC", "GEDID", "LDEDID",
+	"HDCP-MOD", "HDCP-STAT",
+}
+
+// commonCommands are supported by every Protocol 3000 device
+var commonCommands = []string{"", "LOGIN", "LOGOUT", "HELP", "NAME", BuildDate, Model, SerialNumber, FirmwareVersion, ProtocolVersion, IPAddress, Gateway, MACAddress}
+
+// Supports reports whether the model supports the command name
+func (p Profile) Supports(name string) bool {
+	if len(p.Commands) == 0 {
+		return true
+	}
+
+	for _, c := range commonCommands {
+		if strings.EqualFold(c, name) {
+			return true
+		}
+	}
+
+	for _, c := range p.Commands {
+		if strings.EqualFold(c, name) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,57 @@
+package kramer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+type dialFunc func(context.Context) (net.Conn, error)
+
+func (f dialFunc) Dial(ctx context.Context) (net.Conn, error) {
+	return f(ctx)
+}
+
+func TestSupports(t *testing.T) {
+	tests := []struct {
+		profile   Profile
+		name      string
+		supported bool
+	}{
+		{ProfileVS44, "VID", true},
+		{ProfileVS44, "vid", true},
+		{ProfileVS44, Model, true},
+		{ProfileVS44, "LABEL", true},
+		{ProfileVS44, "X-MUTE", false},
+		{ProfileVS44, "ROUTE", false},
+		{ProfileVP558, "ROUTE", true},
+		{ProfileVP558, "VID", false},
+		{ProfileAFM20DSP, "X-AUD-LVL", true},
+		{ProfileAFM20DSP, "VMUTE", false},
+		{Profile{}, "ANYTHING", true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.profile.Supports(tt.name); got != tt.supported {
+			t.Errorf("%s supports %s = %v, want %v", tt.profile.Model, tt.name, got, tt.supported)
+		}
+	}
+}
+
+func TestUnsupportedCommand(t *testing.T) {
+	d := NewDevice("127.0.0.1", ProfileVS44, WithTransport(dialFunc(func(context.Context) (net.Conn, error) {
+		t.Errorf("dialed the device for an unsupported command")
+		return nil, errors.New("unreachable")
+	})))
+
+	_, err := d.Do(context.Background(), NewCommand("X-MUTE", "OUT.ANALOG_AUDIO.1.AUDIO.1", "1"))
+	if !errors.Is(err, ErrCommandNotAvailable) {
+		t.Errorf("got %v, want %v", err, ErrCommandNotAvailable)
+	}
+
+	_, err = d.Batch(context.Background(), NewQuery("VID", "1"), NewQuery("ROUTE", "1", "1"))
+	if !errors.Is(err, ErrCommandNotAvailable) {
+		t.Errorf("got %v, want %v", err, ErrCommandNotAvailable)
+	}
+}
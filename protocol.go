@@ -0,0 +1,188 @@
+package kramer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReplyStatus is the trailing status of a Protocol 3000 reply
+type ReplyStatus int
+
+const (
+	// StatusNone is used for query replies and notifications, which carry no status
+	StatusNone ReplyStatus = iota
+	// StatusOK means the device accepted the command
+	StatusOK
+	// StatusErr means the device rejected the command
+	StatusErr
+)
+
+func (s ReplyStatus) String() string {
+	switch s {
+	case StatusOK:
+		return "OK"
+	case StatusErr:
+		return "ERR"
+	default:
+		return ""
+	}
+}
+
+// Reply is a parsed Protocol 3000 reply, e.g. "~01@VID 1>2 OK".
+type Reply struct {
+	MachineID int
+	Command   string
+	Params    []string
+	Status    ReplyStatus
+
+	// ErrCode is the code following ERR when Status is StatusErr
+	ErrCode int
+}
+
+// ParseReply parses a single Protocol 3000 reply line, in the form "~01@CMD param1,param2[ OK|ERR nnn]".
+// A reply with no command (e.g. "~01@ OK" or "~01@ERR 002") has an empty Command.
+func ParseReply(b []byte) (Reply, error) {
+	var reply Reply
+
+	line := strings.TrimSpace(string(b))
+	if !strings.HasPrefix(line, "~") {
+		return reply, fmt.Errorf("malformed reply %q: missing '~' prefix", line)
+	}
+
+	at := strings.IndexByte(line, '@')
+	if at < 0 {
+		return reply, fmt.Errorf("malformed reply %q: missing '@'", line)
+	}
+
+	id, err := strconv.Atoi(line[1:at])
+	if err != nil {
+		return reply, fmt.Errorf("malformed reply %q: invalid machine id: %w", line, err)
+	}
+
+	reply.MachineID = id
+
+	rest, err := splitStatus(line[at+1:], &reply)
+	if err != nil {
+		return reply, fmt.Errorf("malformed reply %q: %w", line, err)
+	}
+
+	if len(rest) == 0 {
+		if reply.Status == StatusNone {
+			return reply, fmt.Errorf("malformed reply %q: empty reply", line)
+		}
+
+		return reply, nil
+	}
+
+	// the parameters are everything after the first space, kept as they were sent
+	name, params := rest, ""
+	if i := strings.IndexByte(rest, ' '); i >= 0 {
+		name, params = rest[:i], strings.TrimSpace(rest[i+1:])
+	}
+
+	if !validCommandName(name) {
+		return reply, fmt.Errorf("malformed reply %q: invalid command name %q", line, name)
+	}
+
+	reply.Command = name
+
+	if len(params) > 0 {
+		for _, p := range strings.Split(params, ",") {
+			reply.Params = append(reply.Params, strings.TrimSpace(p))
+		}
+	}
+
+	return reply, nil
+}
+
+// Err returns an *Error describing the reply if the device rejected the command
+func (r Reply) Err() error {
+	if r.Status != StatusErr {
+		return nil
+	}
+
+	return &Error{
+		Code:    r.ErrCode,
+		Command: r.Command,
+	}
+}
+
+// Value returns the reply parameters joined back together
+func (r Reply) Value() string {
+	return strings.Join(r.Params, ",")
+}
+
+func (r Reply) String() string {
+	parts := []string{r.Command}
+	if len(r.Params) > 0 {
+		parts = append(parts, r.Value())
+	}
+
+	switch r.Status {
+	case StatusOK:
+		parts = append(parts, "OK")
+	case StatusErr:
+		parts = append(parts, fmt.Sprintf("ERR %03d", r.ErrCode))
+	}
+
+	return fmt.Sprintf("~%02d@%s", r.MachineID, strings.TrimSpace(strings.Join(parts, " ")))
+}
+
+// splitStatus sets reply's status from the OK or ERR nnn that follows the last
+// parameter of s, and returns s without it
+func splitStatus(s string, reply *Reply) (string, error) {
+	s = strings.TrimSpace(s)
+
+	if s == "OK" || strings.HasSuffix(s, " OK") {
+		reply.Status = StatusOK
+		return strings.TrimSpace(strings.TrimSuffix(s, "OK")), nil
+	}
+
+	i := strings.LastIndex(s, "ERR ")
+	if i < 0 || (i > 0 && s[i-1] != ' ') || strings.ContainsAny(s[i+4:], " ,") {
+		return s, nil
+	}
+
+	code, err := strconv.Atoi(s[i+4:])
+	if err != nil {
+		return s, fmt.Errorf("invalid error code: %w", err)
+	}
+
+	reply.Status = StatusErr
+	reply.ErrCode = code
+
+	return strings.TrimSpace(s[:i]), nil
+}
+
+// queryReply returns r as the reply to a query. Replies to queries never carry
+// a status, so a trailing OK is part of the last parameter (e.g. a label of "Room OK").
+func queryReply(r Reply) Reply {
+	if r.Status != StatusOK || len(r.Params) == 0 {
+		return r
+	}
+
+	params := make([]string, len(r.Params))
+	copy(params, r.Params)
+	params[len(params)-1] += " OK"
+
+	r.Params = params
+	r.Status = StatusNone
+
+	return r
+}
+
+func validCommandName(s string) bool {
+	for _, c := range s {
+		switch {
+		case c >= 'A' && c <= 'Z':
+		case c >= 'a' && c <= 'z':
+		case c >= '0' && c <= '9':
+		case c == '-' || c == '_':
+		default:
+			return false
+		}
+	}
+
+	return len(s) > 0
+}
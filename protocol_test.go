@@ -0,0 +1,185 @@
+package kramer
+
+import (
+	"bufio"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/byuoitav/connpool"
+)
+
+func TestParseReply(t *testing.T) {
+	tests := []struct {
+		line  string
+		reply Reply
+		err   bool
+	}{
+		{
+			line:  "~01@ OK\r\n",
+			reply: Reply{MachineID: 1, Status: StatusOK},
+		},
+		{
+			line:  "~01@VID 1>2 OK\r\n",
+			reply: Reply{MachineID: 1, Command: "VID", Params: []string{"1>2"}, Status: StatusOK},
+		},
+		{
+			line:  "~01@VID ERR 003\r\n",
+			reply: Reply{MachineID: 1, Command: "VID", Status: StatusErr, ErrCode: 3},
+		},
+		{
+			line:  "~01@ERR 002\r\n",
+			reply: Reply{MachineID: 1, Status: StatusErr, ErrCode: 2},
+		},
+		{
+			line:  "~12@MODEL VS-44\r\n",
+			reply: Reply{MachineID: 12, Command: "MODEL", Params: []string{"VS-44"}},
+		},
+		{
+			line:  "~01@VID 1>1,2>2,3>3,4>4\r\n",
+			reply: Reply{MachineID: 1, Command: "VID", Params: []string{"1>1", "2>2", "3>3", "4>4"}},
+		},
+		{
+			line:  "~01@LABEL 0,1,Lectern  PC\r\n",
+			reply: Reply{MachineID: 1, Command: "LABEL", Params: []string{"0", "1", "Lectern  PC"}},
+		},
+		{
+			line:  "~01@LABEL 0,1,Room OK OK\r\n",
+			reply: Reply{MachineID: 1, Command: "LABEL", Params: []string{"0", "1", "Room OK"}, Status: StatusOK},
+		},
+		{
+			line:  "~01@LOGIN ERR 004\r\n",
+			reply: Reply{MachineID: 1, Command: "LOGIN", Status: StatusErr, ErrCode: 4},
+		},
+		{line: "Welcome to Kramer Electronics!\r\n", err: true},
+		{line: "~01@\r\n", err: true},
+		{line: "~xx@ OK\r\n", err: true},
+		{line: "~01@VID ERR x\r\n", err: true},
+		{line: "~01@V!D 1>2\r\n", err: true},
+	}
+
+	for _, tt := range tests {
+		reply, err := ParseReply([]byte(tt.line))
+		switch {
+		case tt.err && err == nil:
+			t.Errorf("ParseReply(%q): expected an error, got %+v", tt.line, reply)
+		case !tt.err && err != nil:
+			t.Errorf("ParseReply(%q): %s", tt.line, err)
+		case !tt.err && !reflect.DeepEqual(reply, tt.reply):
+			t.Errorf("ParseReply(%q):\n\tgot  %+v\n\twant %+v", tt.line, reply, tt.reply)
+		}
+	}
+}
+
+func TestIsReply(t *testing.T) {
+	tests := []struct {
+		cmd   Command
+		line  string
+		reply bool
+	}{
+		{NewCommand("VID", "1>2"), "~01@VID 1>2 OK", true},
+		{NewCommand("VID", "1>2"), "~01@VID ERR 003", true},
+		{NewCommand("VID", "1>2"), "~01@ERR 002", true},
+		{NewCommand("VID", "1>2"), "~01@VID 1>2", false},
+		{NewCommand("VID", "1>2"), "~01@AUD 1>2 OK", false},
+		{NewQuery("VID", "2"), "~01@VID 1>2", true},
+		{NewQuery("VID", "2"), "~01@VID 3>1", false},
+		{NewQuery("VID", "*"), "~01@VID 1>1,2>2", true},
+		{Command{Name: "VID", Query: true, Params: []string{"*"}, replyParams: 2}, "~01@VID 1>1,2>2", true},
+		{Command{Name: "VID", Query: true, Params: []string{"*"}, replyParams: 4}, "~01@VID 3>1", false},
+		{NewQuery("HDCP-STAT", "0", "1"), "~01@HDCP-STAT 0,1,1", true},
+		{NewQuery("HDCP-STAT", "0", "1"), "~01@HDCP-STAT 1,1,1", false},
+		{NewQuery("MODEL").ToMachine(2), "~02@MODEL VS-44", true},
+		{NewQuery("MODEL").ToMachine(2), "~01@MODEL VS-44", false},
+		{NewQuery("MODEL"), "~05@MODEL VS-44", true},
+	}
+
+	for _, tt := range tests {
+		reply, err := ParseReply([]byte(tt.line))
+		if err != nil {
+			t.Fatalf("ParseReply(%q): %s", tt.line, err)
+		}
+
+		if got := tt.cmd.isReply(reply); got != tt.reply {
+			t.Errorf("%s isReply(%q) = %v, want %v", tt.cmd, tt.line, got, tt.reply)
+		}
+	}
+}
+
+// fakeExchange runs exchange for cmds against a device that answers with lines
+func fakeExchange(cmds []Command, lines ...string) ([]Reply, []Reply, error) {
+	client, device := net.Pipe()
+	defer client.Close()
+	defer device.Close()
+
+	go func() {
+		// read the command before answering, since net.Pipe is synchronous
+		if _, err := bufio.NewReader(device).ReadBytes(CARRIAGE_RETURN); err != nil {
+			return
+		}
+
+		for _, line := range lines {
+			if _, err := device.Write([]byte(line + "\r\n")); err != nil {
+				return
+			}
+		}
+	}()
+
+	var skipped []Reply
+	replies, err := exchange(connpool.Wrap(client), cmds, func(r Reply) {
+		skipped = append(skipped, r)
+	})
+
+	return replies, skipped, err
+}
+
+func TestExchangeNotifications(t *testing.T) {
+	cmds := []Command{NewCommand("VID", "1>2"), NewQuery("VID", "3"), NewQuery("LABEL", "0", "1")}
+
+	replies, skipped, err := fakeExchange(cmds,
+		"~01@VID 4>1",
+		"~01@VID 2>3",
+		"~01@VID 1>2",
+		"~01@VID 1>2 OK",
+		"~01@LABEL 0,1,Room OK",
+	)
+	if err != nil {
+		t.Fatalf("exchange: %s", err)
+	}
+
+	want := []string{"~01@VID 1>2 OK", "~01@VID 2>3", "~01@LABEL 0,1,Room OK"}
+	for i := range want {
+		if replies[i].String() != want[i] {
+			t.Errorf("reply %d: got %s, want %s", i, replies[i], want[i])
+		}
+	}
+
+	if replies[2].Status != StatusNone {
+		t.Errorf("query reply has status %s", replies[2].Status)
+	}
+
+	if len(skipped) != 2 || skipped[0].String() != "~01@VID 4>1" || skipped[1].String() != "~01@VID 1>2" {
+		t.Errorf("expected the notifications to be skipped, got %v", skipped)
+	}
+}
+
+func TestExchangeAllRoutes(t *testing.T) {
+	query := NewQuery("VID", "*")
+	query.replyParams = 4
+
+	replies, skipped, err := fakeExchange([]Command{query},
+		"~01@VID 3>1",
+		"~01@VID 1>1,2>2,3>3,4>4",
+	)
+	if err != nil {
+		t.Fatalf("exchange: %s", err)
+	}
+
+	if replies[0].Value() != "1>1,2>2,3>3,4>4" {
+		t.Errorf("got reply %s, want every route", replies[0])
+	}
+
+	if len(skipped) != 1 || skipped[0].Value() != "3>1" {
+		t.Errorf("expected the notification to be skipped, got %v", skipped)
+	}
+}
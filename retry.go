@@ -0,0 +1,100 @@
+package kramer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// RetryPolicy controls how commands are retried after a transient failure,
+// like a dropped connection, a timeout or a busy device (ERR 006).
+// Commands that aren't idempotent are never retried.
+type RetryPolicy struct {
+	// Attempts is the number of times a command is tried. 0 or 1 disables retries.
+	Attempts int
+
+	// Backoff is how long to wait before the first retry. It doubles
+	// after every retry, up to MaxBackoff (if set).
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// Jitter randomizes each wait by up to +/- Jitter (0-1) of it, so that
+	// several clients don't retry at the same time
+	Jitter float64
+}
+
+// DefaultRetryPolicy is used unless another policy is set
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:   3,
+	Backoff:    250 * time.Millisecond,
+	MaxBackoff: 2 * time.Second,
+	Jitter:     0.2,
+}
+
+// NoRetries disables retries
+var NoRetries = RetryPolicy{Attempts: 1}
+
+// backoff returns how long to wait before the nth retry (starting at 1)
+func (p RetryPolicy) backoff(n int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < n; i++ {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			d = p.MaxBackoff
+			break
+		}
+	}
+
+	if p.Jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(d))
+	}
+
+	return d
+}
+
+// do calls fn until it succeeds, returns an error that isn't worth retrying,
+// or the policy runs out of attempts. fn is only called once if idempotent isn't set.
+func (p RetryPolicy) do(ctx context.Context, log Logger, idempotent bool, fn func() error) error {
+	err := fn()
+
+	for n := 1; n < p.Attempts && idempotent && err != nil && retryable(err); n++ {
+		wait := p.backoff(n)
+		warnf(log, "retrying in %v (attempt %d/%d): %s", wait, n+1, p.Attempts, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+
+		err = fn()
+	}
+
+	return err
+}
+
+// retryable reports whether err is transient, i.e. whether trying again might work
+func retryable(err error) bool {
+	var perr *Error
+	var serr *syncError
+
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.Is(err, ErrLoginFailed):
+		return false
+	case errors.As(err, &serr):
+		// the device answered, so sending it again won't help
+		return false
+	case errors.As(err, &perr):
+		return errors.Is(perr, ErrBusy)
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	}
+
+	var nerr net.Error
+	return errors.As(err, &nerr)
+}
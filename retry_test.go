@@ -0,0 +1,98 @@
+package kramer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err       error
+		retryable bool
+	}{
+		{&connError{io.EOF}, true},
+		{&connError{&net.OpError{Op: "read", Err: errors.New("connection reset")}}, true},
+		{&syncError{errors.New("malformed reply")}, false},
+		{ErrBusy, true},
+		{ErrCommandNotAvailable, false},
+		{context.DeadlineExceeded, false},
+	}
+
+	for _, tt := range tests {
+		if got := retryable(tt.err); got != tt.retryable {
+			t.Errorf("retryable(%v) = %v, want %v", tt.err, got, tt.retryable)
+		}
+	}
+
+	if unavailable(&syncError{errors.New("malformed reply")}) {
+		t.Errorf("a device that answers shouldn't be unavailable")
+	}
+}
+
+// TestMalformedReply checks that a reply that can't be parsed isn't retried,
+// and doesn't count against the device
+func TestMalformedReply(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+	defer ln.Close()
+
+	var sent int32
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(c net.Conn) {
+				defer c.Close()
+
+				r := bufio.NewReader(c)
+				for {
+					line, err := r.ReadString(CARRIAGE_RETURN)
+					if err != nil {
+						return
+					}
+
+					if line == "#\r" {
+						c.Write([]byte("~01@ OK\r\n"))
+						continue
+					}
+
+					atomic.AddInt32(&sent, 1)
+					c.Write([]byte("~01@MOD!EL garbage\r\n"))
+				}
+			}(c)
+		}
+	}()
+
+	breaker := &Breaker{Threshold: 1}
+	d := NewDevice("127.0.0.1", ProfileVS44,
+		WithTransport(TCPTransport{Address: "127.0.0.1", Port: ln.Addr().(*net.TCPAddr).Port}),
+		WithRetryPolicy(RetryPolicy{Attempts: 3, Backoff: time.Millisecond}),
+		WithBreaker(breaker),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := d.Do(ctx, NewQuery("MODEL")); err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	if n := atomic.LoadInt32(&sent); n != 1 {
+		t.Errorf("command was sent %d times, want 1", n)
+	}
+
+	if breaker.State() != BreakerClosed {
+		t.Errorf("breaker is %s, want closed", breaker.State())
+	}
+}
@@ -0,0 +1,83 @@
+package kramer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Route is what is routed to an output of a switcher
+type Route struct {
+	Video string
+	Audio string
+
+	// Breakaway is set if the audio comes from a different input than the video
+	Breakaway bool
+}
+
+// SetVideoInput changes only the video input on the given output to input
+func (vs *Kramer4x4) SetVideoInput(ctx context.Context, output, input string) error {
+	return vs.setRoutes(ctx, "VID", map[string]string{output: input})
+}
+
+// SetAVInput changes the audio and video input on the given output to input
+// with a single #AV command, on devices that support it
+func (vs *Kramer4x4) SetAVInput(ctx context.Context, output, input string) error {
+	return vs.setRoutes(ctx, "AV", map[string]string{output: input})
+}
+
+// SetAudioInput changes only the audio input on the given output to input,
+// breaking the output's audio away from its video
+func (vs *Kramer4x4) SetAudioInput(ctx context.Context, output, input string) error {
+	return vs.setRoutes(ctx, "AUD", map[string]string{output: input})
+}
+
+// GetVideoInputs returns the video input routed to each output (output -> input)
+func (vs *Kramer4x4) GetVideoInputs(ctx context.Context) (map[string]string, error) {
+	return vs.getInputs(ctx, "VID")
+}
+
+// GetAudioInputs returns the audio input routed to each output (output -> input)
+func (vs *Kramer4x4) GetAudioInputs(ctx context.Context) (map[string]string, error) {
+	return vs.getInputs(ctx, "AUD")
+}
+
+func (vs *Kramer4x4) getInputs(ctx context.Context, name string) (map[string]string, error) {
+	routes, err := vs.readRoutes(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get %s routes: %w", name, err)
+	}
+
+	inputs := make(map[string]string, len(routes))
+	for out, in := range routes {
+		inputs[strconv.Itoa(out)] = strconv.Itoa(in)
+	}
+
+	return inputs, nil
+}
+
+// Routing returns the video and audio routed to each output
+func (vs *Kramer4x4) Routing(ctx context.Context) (map[string]Route, error) {
+	video, err := vs.GetVideoInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	audio, err := vs.GetAudioInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	routing := make(map[string]Route, len(video))
+	for out, in := range video {
+		r := Route{
+			Video: in,
+			Audio: audio[out],
+		}
+		r.Breakaway = r.Audio != r.Video
+
+		routing[out] = r
+	}
+
+	return routing, nil
+}
@@ -0,0 +1,76 @@
+//go:build linux && (amd64 || 386 || arm || arm64)
+// +build linux
+// +build amd64 386 arm arm64
+
+package kramer
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// cbaud is the termios baud rate mask (CBAUD), which package syscall doesn't define.
+// It (and the Termios layout) is only the same on the architectures this file is built for.
+const cbaud = 0x100f
+
+var baudRates = map[int]uint32{
+	9600:   syscall.B9600,
+	19200:  syscall.B19200,
+	38400:  syscall.B38400,
+	57600:  syscall.B57600,
+	115200: syscall.B115200,
+	230400: syscall.B230400,
+}
+
+// openSerial opens device as a raw 8N1 serial port at the given baud rate
+func openSerial(device string, baud int) (net.Conn, error) {
+	speed, ok := baudRates[baud]
+	if !ok {
+		return nil, fmt.Errorf("unsupported baud rate %d", baud)
+	}
+
+	// O_NONBLOCK lets the runtime poller handle the file, so deadlines work
+	f, err := os.OpenFile(device, os.O_RDWR|syscall.O_NOCTTY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %s: %w", device, err)
+	}
+
+	rc, err := f.SyscallConn()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("unable to configure %s: %w", device, err)
+	}
+
+	var errno syscall.Errno
+	err = rc.Control(func(fd uintptr) {
+		var t syscall.Termios
+		if _, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TCGETS, uintptr(unsafe.Pointer(&t))); errno != 0 {
+			return
+		}
+
+		t.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+		t.Oflag &^= syscall.OPOST
+		t.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+		t.Cflag &^= syscall.CSIZE | syscall.PARENB | syscall.CSTOPB | cbaud
+		t.Cflag |= syscall.CS8 | syscall.CREAD | syscall.CLOCAL | speed
+		t.Ispeed = speed
+		t.Ospeed = speed
+		t.Cc[syscall.VMIN] = 1
+		t.Cc[syscall.VTIME] = 0
+
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TCSETS, uintptr(unsafe.Pointer(&t)))
+	})
+	if err == nil && errno != 0 {
+		err = errno
+	}
+
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("unable to configure %s: %w", device, err)
+	}
+
+	return &serialConn{f}, nil
+}
@@ -0,0 +1,77 @@
+//go:build linux && (amd64 || 386 || arm || arm64)
+// +build linux
+// +build amd64 386 arm arm64
+
+package kramer
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// openPty opens a pseudo terminal, returning its master and the path of its slave
+func openPty(t *testing.T) (*os.File, string) {
+	m, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("unable to open pty: %s", err)
+	}
+
+	var unlock int32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, m.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); errno != 0 {
+		m.Close()
+		t.Fatalf("unable to unlock pty: %s", errno)
+	}
+
+	var n uint32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, m.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n))); errno != 0 {
+		m.Close()
+		t.Fatalf("unable to get pty number: %s", errno)
+	}
+
+	return m, fmt.Sprintf("/dev/pts/%d", n)
+}
+
+func TestSerialTransport(t *testing.T) {
+	m, name := openPty(t)
+	defer m.Close()
+
+	// the device answers the handshake and MODEL?
+	go func() {
+		r := bufio.NewReader(m)
+		for {
+			line, err := r.ReadString(CARRIAGE_RETURN)
+			if err != nil {
+				return
+			}
+
+			switch line {
+			case "#\r":
+				m.Write([]byte("~01@ OK\r\n"))
+			case "#MODEL?\r":
+				m.Write([]byte("~01@MODEL VS-44DT\r\n"))
+			default:
+				m.Write([]byte("~01@ERR 002\r\n"))
+			}
+		}
+	}()
+
+	d := NewDevice("serial", ProfileVS44, WithTransport(SerialTransport{Device: name, BaudRate: 9600}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reply, err := d.Do(ctx, NewQuery("MODEL"))
+	if err != nil {
+		t.Fatalf("unable to get model: %s", err)
+	}
+
+	if reply.Value() != "VS-44DT" {
+		t.Errorf("got model %q, want %q", reply.Value(), "VS-44DT")
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !linux || !(amd64 || 386 || arm || arm64)
+// +build !linux !amd64,!386,!arm,!arm64
+
+package kramer
+
+import (
+	"errors"
+	"net"
+)
+
+func openSerial(device string, baud int) (net.Conn, error) {
+	return nil, errors.New("serial transport is only supported on linux (amd64, 386, arm and arm64)")
+}
@@ -3,7 +3,6 @@ package kramer
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/byuoitav/common/log"
 )
@@ -16,14 +15,10 @@ func (vs *Kramer4x4) SetFrontLock(ctx context.Context, state bool) error {
 		num = 1
 	}
 
-	cmd := []byte(fmt.Sprintf("#LOCK-FP %v\r\n", num))
+	cmd := NewCommand("LOCK-FP", fmt.Sprintf("%v", num))
 
-	resp, err := vs.SendCommand(ctx, cmd)
-	switch {
-	case err != nil:
+	if _, err := vs.Do(ctx, cmd); err != nil {
 		return fmt.Errorf("unable to send command: %w", err)
-	case !strings.Contains(string(resp), "OK"):
-		return fmt.Errorf("Incorrect response for command (%s). (Response: %s)", cmd, resp)
 	}
 
 	return nil
@@ -0,0 +1,73 @@
+package kramer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Signals is whether there is a signal on each input (#SIGNAL?), and
+// whether a display is connected to each output (#DISPLAY?)
+type Signals struct {
+	Inputs  map[string]bool
+	Outputs map[string]bool
+}
+
+// Signals returns the signal on every input and the display on every output at once
+func (vs *Kramer4x4) Signals(ctx context.Context) (Signals, error) {
+	return vs.signals(ctx)
+}
+
+// Signals returns the signal on every input and the display on every output at once
+func (vsdsp *KramerVP558) Signals(ctx context.Context) (Signals, error) {
+	return vsdsp.signals(ctx)
+}
+
+func (d *Device) signals(ctx context.Context) (Signals, error) {
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return Signals{}, err
+	}
+
+	var cmds []Command
+	for i := 0; i < dims.Inputs; i++ {
+		cmds = append(cmds, NewQuery(Signal, strconv.Itoa(d.devicePort(i))))
+	}
+
+	for o := 0; o < dims.Outputs; o++ {
+		cmds = append(cmds, NewQuery("DISPLAY", strconv.Itoa(d.devicePort(o))))
+	}
+
+	replies, err := d.Batch(ctx, cmds...)
+	if err != nil {
+		return Signals{}, fmt.Errorf("unable to get signals: %w", err)
+	}
+
+	signals := Signals{
+		Inputs:  make(map[string]bool, dims.Inputs),
+		Outputs: make(map[string]bool, dims.Outputs),
+	}
+
+	for i, reply := range replies {
+		// e.g. "~01@SIGNAL 1,1" or "~01@DISPLAY 2,0"
+		if len(reply.Params) != 2 {
+			return Signals{}, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		port, err := strconv.Atoi(reply.Params[0])
+		if err != nil {
+			return Signals{}, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		key := strconv.Itoa(d.localPort(port))
+		active := reply.Params[1] == "1"
+
+		if i < dims.Inputs {
+			signals.Inputs[key] = active
+		} else {
+			signals.Outputs[key] = active
+		}
+	}
+
+	return signals, nil
+}
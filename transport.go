@@ -0,0 +1,117 @@
+package kramer
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+)
+
+const (
+	_defaultTCPPort  = 5000
+	_defaultUDPPort  = 50000
+	_defaultBaudRate = 115200
+)
+
+// Transport opens connections to a Protocol 3000 device
+type Transport interface {
+	Dial(ctx context.Context) (net.Conn, error)
+}
+
+// exclusiveTransport is implemented by transports that can only have
+// a single connection open to the device at a time
+type exclusiveTransport interface {
+	exclusive() bool
+}
+
+func isExclusive(t Transport) bool {
+	e, ok := t.(exclusiveTransport)
+	return ok && e.exclusive()
+}
+
+// TCPTransport connects to a device over TCP. Port defaults to 5000.
+type TCPTransport struct {
+	Address string
+	Port    int
+}
+
+// Dial .
+func (t TCPTransport) Dial(ctx context.Context) (net.Conn, error) {
+	port := t.Port
+	if port == 0 {
+		port = _defaultTCPPort
+	}
+
+	d := net.Dialer{}
+	return d.DialContext(ctx, "tcp", net.JoinHostPort(t.Address, strconv.Itoa(port)))
+}
+
+// UDPTransport sends Protocol 3000 over UDP. Port defaults to 50000.
+type UDPTransport struct {
+	Address string
+	Port    int
+}
+
+// Dial .
+func (t UDPTransport) Dial(ctx context.Context) (net.Conn, error) {
+	port := t.Port
+	if port == 0 {
+		port = _defaultUDPPort
+	}
+
+	d := net.Dialer{}
+	return d.DialContext(ctx, "udp", net.JoinHostPort(t.Address, strconv.Itoa(port)))
+}
+
+func (t UDPTransport) exclusive() bool {
+	return true
+}
+
+// SerialTransport talks to a device over an RS-232 serial line (8N1).
+// BaudRate defaults to 115200.
+type SerialTransport struct {
+	Device   string
+	BaudRate int
+}
+
+// Dial .
+func (t SerialTransport) Dial(ctx context.Context) (net.Conn, error) {
+	baud := t.BaudRate
+	if baud == 0 {
+		baud = _defaultBaudRate
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return openSerial(t.Device, baud)
+}
+
+func (t SerialTransport) exclusive() bool {
+	return true
+}
+
+// serialAddr is the net.Addr of a serial connection
+type serialAddr string
+
+func (a serialAddr) Network() string {
+	return "serial"
+}
+
+func (a serialAddr) String() string {
+	return string(a)
+}
+
+// serialConn adapts a serial port to a net.Conn
+type serialConn struct {
+	*os.File
+}
+
+func (c *serialConn) LocalAddr() net.Addr {
+	return serialAddr(c.Name())
+}
+
+func (c *serialConn) RemoteAddr() net.Addr {
+	return serialAddr(c.Name())
+}
@@ -0,0 +1,91 @@
+package kramer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeUDPDevice answers each datagram it gets with a single datagram, like a
+// Protocol 3000 device listening on UDP. It returns the port it listens on.
+func fakeUDPDevice(t *testing.T) (int, func()) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			var reply string
+			switch string(buf[:n]) {
+			case "#\r":
+				reply = "Welcome to Kramer Electronics!\r\n~01@ OK\r\n"
+			case "#LOGIN admin,pw\r":
+				reply = "~01@LOGIN admin,pw OK\r\n"
+			case "#MODEL?\r":
+				reply = "~01@MODEL VS-44\r\n"
+			default:
+				reply = "~01@ERR 002\r\n"
+			}
+
+			if _, err := conn.WriteTo([]byte(reply), addr); err != nil {
+				return
+			}
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port, func() { conn.Close() }
+}
+
+func TestUDPTransport(t *testing.T) {
+	port, stop := fakeUDPDevice(t)
+	defer stop()
+
+	d := NewDevice("127.0.0.1", ProfileVS44,
+		WithTransport(UDPTransport{Address: "127.0.0.1", Port: port}),
+		WithCredentials("admin", "pw"),
+		WithHandshakeTimeout(time.Second),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		reply, err := d.Do(ctx, NewQuery("MODEL"))
+		if err != nil {
+			t.Fatalf("unable to get model: %s", err)
+		}
+
+		if reply.Value() != "VS-44" {
+			t.Errorf("got model %q, want %q", reply.Value(), "VS-44")
+		}
+	}
+
+	if d.Banner() != "Welcome to Kramer Electronics!" {
+		t.Errorf("got banner %q", d.Banner())
+	}
+}
+
+func TestLoginInvalidPassword(t *testing.T) {
+	port, stop := fakeUDPDevice(t)
+	defer stop()
+
+	conn, err := UDPTransport{Address: "127.0.0.1", Port: port}.Dial(context.Background())
+	if err != nil {
+		t.Fatalf("unable to dial: %s", err)
+	}
+	defer conn.Close()
+
+	err = login(newBufferedConn(conn), "admin", "p,w|x")
+	if err == nil || errors.Is(err, ErrLoginFailed) {
+		t.Fatalf("expected the password to be rejected before it was sent, got %v", err)
+	}
+}
@@ -23,6 +23,13 @@ type Via struct {
 	Username string
 	Password string
 	Logger   Logger
+
+	// Retry controls how commands are retried after transient failures.
+	// DefaultRetryPolicy is used if it is nil.
+	Retry *RetryPolicy
+
+	// Breaker, if set, fails commands fast with ErrDeviceUnavailable once the VIA stops answering
+	Breaker *Breaker
 }
 
 // These functions fulfill the DSP driver requirements
@@ -43,7 +50,7 @@ func (v *Via) Volumes(ctx context.Context, block []string) (map[string]int, erro
 func (v *Via) SetVolume(ctx context.Context, block string, volume int) error {
 	_, err := v.SetViaVolume(ctx, volume)
 	if err != nil {
-		return errors.New(fmt.Sprintf("Failed to set volume for %v: %v", v.Address, err.Error()))
+		return fmt.Errorf("Failed to set volume for %v: %w", v.Address, err)
 	}
 
 	return nil
@@ -53,41 +60,112 @@ func (v *Via) SetVolume(ctx context.Context, block string, volume int) error {
 func (v *Via) GetInfo(ctx context.Context) (interface{}, error) {
 	info, err := v.GetHardwareInfo(ctx)
 	if err != nil {
-		return info, fmt.Errorf("Failed to get hardware information: %s", err)
+		return info, fmt.Errorf("Failed to get hardware information: %w", err)
 	}
 	return info, nil
 }
 
 // End of DSP Driver requirements
-func getConnection(address string) (*net.TCPConn, error) {
-	radder, err := net.ResolveTCPAddr("tcp", address+":9982")
+func getConnection(ctx context.Context, address string) (*net.TCPConn, error) {
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(address, "9982"))
 	if err != nil {
-		err = fmt.Errorf("error resolving address : %s", err.Error())
+		err = contextError(ctx, fmt.Errorf("error dialing address : %w", err))
 		return nil, err
 	}
 
-	conn, err := net.DialTCP("tcp", nil, radder)
-	if err != nil {
-		err = fmt.Errorf("error dialing address : %s", err.Error())
-		return nil, err
+	return conn.(*net.TCPConn), nil
+}
+
+// watchContext sets conn's deadline to timeout from now (or ctx's deadline, if
+// it is sooner) and interrupts any reads or writes on it once ctx is done.
+// Call stop once done with ctx.
+func watchContext(ctx context.Context, conn net.Conn, timeout time.Duration) (stop func()) {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	conn.SetDeadline(deadline)
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+
+		select {
+		case <-ctx.Done():
+			conn.SetDeadline(time.Unix(1, 0))
+		case <-done:
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-exited
+	}
+}
+
+// contextError wraps ctx.Err() into err if ctx is done, since that is
+// why I/O on a connection from watchContext would have failed
+func contextError(ctx context.Context, err error) error {
+	if ctx.Err() == nil || errors.Is(err, ctx.Err()) {
+		return err
 	}
 
-	return conn, nil
+	return fmt.Errorf("%s: %w", err, ctx.Err())
 }
 
 // SendCommand opens a connection with <addr> and sends the <command> to the via, returning the response from the via, or an error if one occured.
+// Commands other than Reboot and Reset are retried after transient failures.
 func (v *Via) sendCommand(ctx context.Context, cmd command) (string, error) {
+	policy := DefaultRetryPolicy
+	if v.Retry != nil {
+		policy = *v.Retry
+	}
+
+	v.Breaker.setProbe(v.probe)
+	if err := v.Breaker.allow(); err != nil {
+		return "", fmt.Errorf("%s: %w", v.Address, err)
+	}
+
+	idempotent := cmd.Command != viaReboot && cmd.Command != viaReset
+
+	var resp string
+	err := policy.do(ctx, v.Logger, idempotent, func() error {
+		var err error
+		resp, err = v.sendCommandOnce(ctx, cmd)
+		return err
+	})
+	v.Breaker.record(err)
+
+	return resp, err
+}
+
+// probe checks whether the VIA is answering by opening a connection to it,
+// for the breaker to probe the VIA while it's open
+func (v *Via) probe(ctx context.Context) error {
+	conn, err := getConnection(ctx, v.Address)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
+func (v *Via) sendCommandOnce(ctx context.Context, cmd command) (string, error) {
 	// get the connection
 	v.Infof("Opening telnet connection with %s", v.Address)
-	conn, err := getConnection(v.Address)
+	conn, err := getConnection(ctx, v.Address)
 	if err != nil {
 		return "", err
 	}
+	defer conn.Close()
 
-	timeoutDuration := 7 * time.Second
-
-	// Set Read Connection Duration
-	conn.SetReadDeadline(time.Now().Add(timeoutDuration))
+	stop := watchContext(ctx, conn, 7*time.Second)
+	defer stop()
 
 	// login
 	err = v.login(ctx, conn)
@@ -106,14 +184,14 @@ func (v *Via) sendCommand(ctx context.Context, cmd command) (string, error) {
 
 		_, err = conn.Write(b)
 		if err != nil {
-			return "", err
+			return "", contextError(ctx, err)
 		}
 	}
 
 	reader := bufio.NewReader(conn)
 	resp, err := reader.ReadBytes('\n')
 	if err != nil {
-		err = fmt.Errorf("error reading from system: %s", err.Error())
+		err = contextError(ctx, fmt.Errorf("error reading from system: %w", err))
 		v.Errorf(err.Error())
 		return "", err
 	}
@@ -136,7 +214,7 @@ func (v *Via) login(ctx context.Context, conn *net.TCPConn) error {
 	reader := bufio.NewReader(conn)
 	_, err := reader.ReadBytes('\n')
 	if err != nil {
-		err = fmt.Errorf("error reading from system: %s", err.Error())
+		err = contextError(ctx, fmt.Errorf("error reading from system: %w", err))
 		v.Errorf(err.Error())
 		return err
 	}
@@ -151,12 +229,12 @@ func (v *Via) login(ctx context.Context, conn *net.TCPConn) error {
 
 	_, err = conn.Write(b)
 	if err != nil {
-		return err
+		return contextError(ctx, err)
 	}
 
 	resp, err := reader.ReadBytes('\n')
 	if err != nil {
-		err = fmt.Errorf("error reading from system: %s", err.Error())
+		err = contextError(ctx, fmt.Errorf("error reading from system: %w", err))
 		v.Errorf(err.Error())
 		return err
 	}
@@ -130,7 +130,7 @@ func (v *Via) GetHardwareInfo(ctx context.Context) (HardwareInfo, error) {
 
 	serial, err := v.sendCommand(ctx, cmd)
 	if err != nil {
-		return toReturn, fmt.Errorf("failed to get serial number from %s", v.Address)
+		return toReturn, fmt.Errorf("failed to get serial number from %s: %w", v.Address, err)
 	}
 
 	toReturn.SerialNumber = parseResponse(serial, "|")
@@ -140,7 +140,7 @@ func (v *Via) GetHardwareInfo(ctx context.Context) (HardwareInfo, error) {
 
 	version, err := v.sendCommand(ctx, cmd)
 	if err != nil {
-		return toReturn, fmt.Errorf("failed to get the firmware version of %s", v.Address)
+		return toReturn, fmt.Errorf("failed to get the firmware version of %s: %w", v.Address, err)
 	}
 
 	toReturn.FirmwareVersion = parseResponse(version, "|")
@@ -150,7 +150,7 @@ func (v *Via) GetHardwareInfo(ctx context.Context) (HardwareInfo, error) {
 
 	macAddr, err := v.sendCommand(ctx, cmd)
 	if err != nil {
-		return toReturn, fmt.Errorf("failed to get the MAC address of %s", v.Address)
+		return toReturn, fmt.Errorf("failed to get the MAC address of %s: %w", v.Address, err)
 	}
 
 	// get IP information
@@ -158,7 +158,7 @@ func (v *Via) GetHardwareInfo(ctx context.Context) (HardwareInfo, error) {
 
 	ipInfo, err := v.sendCommand(ctx, cmd)
 	if err != nil {
-		return toReturn, fmt.Errorf("failed to get the IP information from %s", v.Address)
+		return toReturn, fmt.Errorf("failed to get the IP information from %s: %w", v.Address, err)
 	}
 
 	hostname, network := parseIPInfo(ipInfo)
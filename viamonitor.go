@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/xml"
 	"net"
+	"time"
 )
 
 type Message struct {
@@ -44,18 +45,25 @@ func (v *Via) Ping(conn *PersistentViaConnection) error {
 func (v *Via) PersistConnection(ctx context.Context) (*PersistentViaConnection, error) {
 	// get the connection
 	v.Infof("Opening persistent telnet connection for reading events from %s", v.Address)
-	gconn, err := getConnection(v.Address)
+	gconn, err := getConnection(ctx, v.Address)
 	if err != nil {
 		return nil, err
 	}
 
 	// login
+	stop := watchContext(ctx, gconn, 7*time.Second)
 	err = v.login(ctx, gconn)
+	stop()
+
 	if err != nil {
 		v.Debugf("Houston, we have a problem logging in. The login failed")
+		gconn.Close()
 		return nil, err
 	}
 
+	// ctx only bounds opening the connection
+	gconn.SetDeadline(time.Time{})
+
 	return &PersistentViaConnection{
 		Conn:   gconn,
 		Reader: bufio.NewReader(gconn),
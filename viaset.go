@@ -19,7 +19,7 @@ func (v *Via) SetViaVolume(ctx context.Context, volume int) (string, error) {
 
 	resp, err := v.sendCommand(ctx, cmd)
 	if err != nil {
-		return "", errors.New(fmt.Sprintf("Error in setting volume on %s", v.Address))
+		return "", fmt.Errorf("Error in setting volume on %s: %w", v.Address, err)
 	}
 
 	return resp, nil
@@ -76,7 +76,7 @@ func (v *Via) SetAlert(ctx context.Context, AlertMessage string) error {
 	resp, err := v.sendCommand(ctx, cmd)
 	if err != nil {
 		v.Errorf("Error in setting alert on %s", v.Address)
-		return fmt.Errorf("Error in setting alert on %s", v.Address)
+		return fmt.Errorf("Error in setting alert on %s: %w", v.Address, err)
 	}
 	clean := strings.TrimRight(resp, "\r\n")
 	sp := strings.Split(clean, "|")
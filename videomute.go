@@ -0,0 +1,240 @@
+package kramer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// VideoMuteMode is what an output shows (#VMUTE)
+type VideoMuteMode int
+
+const (
+	// VideoLive shows the routed input
+	VideoLive VideoMuteMode = iota
+	// VideoDisabled stops sending video, so the display sees no signal
+	VideoDisabled
+	// VideoBlank sends a blank picture, so the display stays in sync
+	VideoBlank
+)
+
+func (m VideoMuteMode) String() string {
+	switch m {
+	case VideoLive:
+		return "live"
+	case VideoDisabled:
+		return "disabled"
+	case VideoBlank:
+		return "blank"
+	default:
+		return "unknown"
+	}
+}
+
+// SetVideoMute blanks (or unblanks) the given output without changing its route
+func (vs *Kramer4x4) SetVideoMute(ctx context.Context, output string, muted bool) error {
+	return vs.setVideoMute(ctx, output, muted)
+}
+
+// VideoMutes returns whether each of outputs is blanked or disabled
+func (vs *Kramer4x4) VideoMutes(ctx context.Context, outputs []string) (map[string]bool, error) {
+	return vs.videoMutes(ctx, outputs)
+}
+
+// SetVideoMuteMode blanks, disables or unmutes the given output
+func (vs *Kramer4x4) SetVideoMuteMode(ctx context.Context, output string, mode VideoMuteMode) error {
+	return vs.setVideoMuteMode(ctx, output, mode)
+}
+
+// VideoMuteModes returns what each of outputs is showing
+func (vs *Kramer4x4) VideoMuteModes(ctx context.Context, outputs []string) (map[string]VideoMuteMode, error) {
+	return vs.videoMuteModes(ctx, outputs)
+}
+
+// SetVideoMute blanks (or unblanks) the given output without changing its route
+func (vsdsp *KramerVP558) SetVideoMute(ctx context.Context, output string, muted bool) error {
+	return vsdsp.setVideoMute(ctx, output, muted)
+}
+
+// VideoMutes returns whether each of outputs is blanked or disabled
+func (vsdsp *KramerVP558) VideoMutes(ctx context.Context, outputs []string) (map[string]bool, error) {
+	return vsdsp.videoMutes(ctx, outputs)
+}
+
+// SetVideoMuteMode blanks, disables or unmutes the given output
+func (vsdsp *KramerVP558) SetVideoMuteMode(ctx context.Context, output string, mode VideoMuteMode) error {
+	return vsdsp.setVideoMuteMode(ctx, output, mode)
+}
+
+// VideoMuteModes returns what each of outputs is showing
+func (vsdsp *KramerVP558) VideoMuteModes(ctx context.Context, outputs []string) (map[string]VideoMuteMode, error) {
+	return vsdsp.videoMuteModes(ctx, outputs)
+}
+
+// SetFreeze freezes (or unfreezes) the given output on the current frame (#FREEZE),
+// on devices that support it
+func (vs *Kramer4x4) SetFreeze(ctx context.Context, output string, frozen bool) error {
+	return vs.setFreeze(ctx, output, frozen)
+}
+
+// Freezes returns whether each of outputs is frozen
+func (vs *Kramer4x4) Freezes(ctx context.Context, outputs []string) (map[string]bool, error) {
+	return vs.freezes(ctx, outputs)
+}
+
+// SetFreeze freezes (or unfreezes) the given output on the current frame (#FREEZE),
+// on devices that support it
+func (vsdsp *KramerVP558) SetFreeze(ctx context.Context, output string, frozen bool) error {
+	return vsdsp.setFreeze(ctx, output, frozen)
+}
+
+// Freezes returns whether each of outputs is frozen
+func (vsdsp *KramerVP558) Freezes(ctx context.Context, outputs []string) (map[string]bool, error) {
+	return vsdsp.freezes(ctx, outputs)
+}
+
+func (d *Device) setVideoMute(ctx context.Context, output string, muted bool) error {
+	mode := VideoLive
+	if muted {
+		mode = VideoBlank
+	}
+
+	return d.setVideoMuteMode(ctx, output, mode)
+}
+
+func (d *Device) videoMutes(ctx context.Context, outputs []string) (map[string]bool, error) {
+	modes, err := d.videoMuteModes(ctx, outputs)
+	if err != nil {
+		return nil, err
+	}
+
+	toReturn := make(map[string]bool, len(modes))
+	for output, mode := range modes {
+		toReturn[output] = mode != VideoLive
+	}
+
+	return toReturn, nil
+}
+
+func (d *Device) setVideoMuteMode(ctx context.Context, output string, mode VideoMuteMode) error {
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return err
+	}
+
+	o, err := parsePort("output", output, dims.Outputs)
+	if err != nil {
+		return err
+	}
+
+	d.Log.Debugf("Setting video mute on output %v to %v on %v", output, mode, d.Address)
+
+	cmd := NewCommand("VMUTE", strconv.Itoa(d.devicePort(o)), strconv.Itoa(int(mode)))
+	if _, err := d.Do(ctx, cmd); err != nil {
+		return fmt.Errorf("unable to set video mute: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Device) videoMuteModes(ctx context.Context, outputs []string) (map[string]VideoMuteMode, error) {
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var cmds []Command
+	for _, output := range outputs {
+		o, err := parsePort("output", output, dims.Outputs)
+		if err != nil {
+			return nil, err
+		}
+
+		cmds = append(cmds, NewQuery("VMUTE", strconv.Itoa(d.devicePort(o))))
+	}
+
+	replies, err := d.Batch(ctx, cmds...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get video mutes: %w", err)
+	}
+
+	toReturn := make(map[string]VideoMuteMode, len(outputs))
+	for i, output := range outputs {
+		reply := replies[i]
+
+		// e.g. "~01@VMUTE 1,2"
+		if len(reply.Params) != 2 {
+			return nil, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		mode, err := strconv.Atoi(reply.Params[1])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		toReturn[output] = VideoMuteMode(mode)
+	}
+
+	return toReturn, nil
+}
+
+func (d *Device) setFreeze(ctx context.Context, output string, frozen bool) error {
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return err
+	}
+
+	o, err := parsePort("output", output, dims.Outputs)
+	if err != nil {
+		return err
+	}
+
+	state := "0"
+	if frozen {
+		state = "1"
+	}
+
+	d.Log.Debugf("Setting freeze on output %v to %v on %v", output, frozen, d.Address)
+
+	if _, err := d.Do(ctx, NewCommand("FREEZE", strconv.Itoa(d.devicePort(o)), state)); err != nil {
+		return fmt.Errorf("unable to set freeze: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Device) freezes(ctx context.Context, outputs []string) (map[string]bool, error) {
+	dims, err := d.Dimensions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var cmds []Command
+	for _, output := range outputs {
+		o, err := parsePort("output", output, dims.Outputs)
+		if err != nil {
+			return nil, err
+		}
+
+		cmds = append(cmds, NewQuery("FREEZE", strconv.Itoa(d.devicePort(o))))
+	}
+
+	replies, err := d.Batch(ctx, cmds...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get freezes: %w", err)
+	}
+
+	toReturn := make(map[string]bool, len(outputs))
+	for i, output := range outputs {
+		reply := replies[i]
+
+		// e.g. "~01@FREEZE 1,1"
+		if len(reply.Params) != 2 {
+			return nil, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
+
+		toReturn[output] = reply.Params[1] == "1"
+	}
+
+	return toReturn, nil
+}
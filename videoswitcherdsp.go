@@ -3,86 +3,35 @@ package kramer
 import (
 	"context"
 	"fmt"
-	"net"
 	"time"
 
 	"github.com/byuoitav/connpool"
 )
 
+// KramerVP558 is a Kramer VP-558 presentation switcher
 type KramerVP558 struct {
-	Address string
-	Log     Logger
-
-	pool *connpool.Pool
-}
-
-// var (
-// 	_defaultTTL   = 30 * time.Second
-// 	_defaultDelay = 500 * time.Millisecond
-// )
-
-type KramerVP558options struct {
-	ttl    time.Duration
-	delay  time.Duration
-	logger Logger
-}
-
-type KramerVP558Option interface {
-	apply(*KramerVP558options)
+	*Device
 }
 
-type KramerVP558optionFunc func(*KramerVP558options)
-
-func (f KramerVP558optionFunc) apply(o *KramerVP558options) {
-	f(o)
-}
+// KramerVP558Option configures a KramerVP558
+type KramerVP558Option = Option
 
+// WithLoggerVSDSP sets the logger used by the KramerVP558.
+//
+// Deprecated: use WithLogger, which (like every Option) works with any driver.
 func WithLoggerVSDSP(l Logger) KramerVP558Option {
-	return KramerVP558optionFunc(func(o *KramerVP558options) {
-		o.logger = l
-	})
+	return WithLogger(l)
 }
 
 func NewVideoSwitcherDsp(addr string, opts ...KramerVP558Option) *KramerVP558 {
-	options := KramerVP558options{
-		ttl:   _defaultTTL,
-		delay: _defaultDelay,
-	}
-
-	for _, o := range opts {
-		o.apply(&options)
+	return &KramerVP558{
+		Device: NewDevice(addr, ProfileVP558, opts...),
 	}
-
-	vsdsp := &KramerVP558{
-		Address: addr,
-		pool: &connpool.Pool{
-			TTL:    options.ttl,
-			Delay:  options.delay,
-			Logger: options.logger,
-		},
-		Log: options.logger,
-	}
-
-	vsdsp.pool.NewConnection = func(ctx context.Context) (net.Conn, error) {
-		d := net.Dialer{}
-		conn, err := d.DialContext(ctx, "tcp", vsdsp.Address+":5000")
-		if err != nil {
-			return nil, fmt.Errorf("unable to open connection: %w", err)
-		}
-
-		select {
-		case <-ctx.Done():
-			return nil, fmt.Errorf("context done before welcome message is sent: %w", ctx.Err())
-		case <-time.After(500 * time.Millisecond):
-		}
-
-		return conn, nil
-	}
-
-	return vsdsp
 }
 
 // SendCommand sends the byte array to the desired address of projector
+//
+// Deprecated: use Do, which encodes the command and parses the reply.
 func (vsdsp *KramerVP558) SendCommand(ctx context.Context, cmd []byte, readAgain bool) ([]byte, error) {
 	var resp []byte
 
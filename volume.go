@@ -21,25 +21,26 @@ const (
 func (dsp *KramerAFM20DSP) Volumes(ctx context.Context, blocks []string) (map[string]int, error) {
 	toReturn := make(map[string]int)
 
+	var cmds []Command
 	for _, block := range blocks {
 		dsp.Log.Infof("sending get volume command", zap.String("block", block))
+		cmds = append(cmds, NewQuery("X-AUD-LVL", fmt.Sprintf("OUT.ANALOG_AUDIO.%s.AUDIO.1", block)))
+	}
 
-		cmd := []byte(fmt.Sprintf("#X-AUD-LVL? OUT.ANALOG_AUDIO.%s.AUDIO.1\r\n", block))
-		resp, err := dsp.SendCommand(ctx, cmd)
-		if err != nil {
-			dsp.Log.Errorf("error sending command: %s", err.Error())
-			return toReturn, fmt.Errorf("error sending command: %w", err)
-		}
+	replies, err := dsp.Batch(ctx, cmds...)
+	if err != nil {
+		dsp.Log.Errorf("error sending command: %s", err.Error())
+		return toReturn, fmt.Errorf("error sending command: %w", err)
+	}
 
-		resps := string(resp)
-		if strings.Contains(resps, "ERR") {
-			return toReturn, fmt.Errorf("an error occured: (command: %s) response: %s)", cmd, resps)
-		}
-		resps = strings.TrimSpace(resps)
+	for i, block := range blocks {
+		reply := replies[i]
 
-		parts := strings.Split(resps, ",")
+		if len(reply.Params) != 2 {
+			return toReturn, fmt.Errorf("unexpected response, unable to parse: %s", reply)
+		}
 
-		dbParts := strings.Split(parts[1], ".")
+		dbParts := strings.Split(reply.Params[1], ".")
 		currentDB, err := strconv.Atoi(dbParts[0])
 		if err != nil {
 			return toReturn, err
@@ -60,19 +61,13 @@ func (dsp *KramerAFM20DSP) SetVolume(ctx context.Context, block string, level in
 
 	dsp.Log.Infof("sending set volume command", zap.String("block", block), zap.Int("level", level))
 
-	cmd := []byte(fmt.Sprintf("#X-AUD-LVL OUT.ANALOG_AUDIO.%s.AUDIO.1, %v\r", block, volumeLevel))
+	cmd := NewCommand("X-AUD-LVL", fmt.Sprintf("OUT.ANALOG_AUDIO.%s.AUDIO.1", block), strconv.Itoa(volumeLevel))
 
-	resp, err := dsp.SendCommand(ctx, cmd)
-	if err != nil {
+	if _, err := dsp.Do(ctx, cmd); err != nil {
 		dsp.Log.Errorf("error sending command: %s", err.Error())
 		return fmt.Errorf("error sending command: %w", err)
 	}
 
-	resps := string(resp)
-	if strings.Contains(resps, "ERR") {
-		return fmt.Errorf("an error occured: (command: %s) response: %s)", cmd, resps)
-	}
-
 	dsp.Log.Infof("successfully set volume", zap.String("block", block), zap.Int("level", level))
 
 	return nil
@@ -102,29 +97,26 @@ func convertBackToVolume(level int) int {
 func (vsdsp *KramerVP558) Volumes(ctx context.Context, blocks []string) (map[string]int, error) {
 	toReturn := make(map[string]int)
 
+	var cmds []Command
 	for _, block := range blocks {
 		vsdsp.Log.Infof("sending get volume command", zap.String("block", block))
+		cmds = append(cmds, NewQuery("AUD-LVL", "1", block))
+	}
 
-		cmd := []byte(fmt.Sprintf("#AUD-LVL? 1,%s\r\n", block))
-		resp, err := vsdsp.SendCommand(ctx, cmd, false)
-		if err != nil {
-			vsdsp.Log.Errorf("error sending command: %s", err.Error())
-			return toReturn, fmt.Errorf("error sending command: %w", err)
-		}
-
-		resps := string(resp)
-		if strings.Contains(resps, "ERR") {
-			return toReturn, fmt.Errorf("an error occured: (command: %s) response: %s)", cmd, resps)
-		}
+	replies, err := vsdsp.Batch(ctx, cmds...)
+	if err != nil {
+		vsdsp.Log.Errorf("error sending command: %s", err.Error())
+		return toReturn, fmt.Errorf("error sending command: %w", err)
+	}
 
-		resps = strings.TrimSpace(resps)
-		parts := strings.Split(resps, ",")
+	for i, block := range blocks {
+		reply := replies[i]
 
-		if len(parts) != 3 {
-			return toReturn, fmt.Errorf("unexpected response, unable to parse: %s", resps)
+		if len(reply.Params) != 3 {
+			return toReturn, fmt.Errorf("unexpected response, unable to parse: %s", reply)
 		}
 
-		volume, err := strconv.Atoi(parts[2])
+		volume, err := strconv.Atoi(reply.Params[2])
 		if err != nil {
 			return toReturn, err
 		}
@@ -139,33 +131,13 @@ func (vsdsp *KramerVP558) Volumes(ctx context.Context, blocks []string) (map[str
 // Audio inputs are formatted 0:0 - 4:2, and audio level is between 0-100.
 // for more information on Audio Inputs reference https://cdn.kramerav.com/web/downloads/manuals/vp-558_rev_4.pdf (pg. 64)
 func (vsdsp *KramerVP558) SetVolume(ctx context.Context, block string, level int) error {
-	var cmd []byte
-
 	vsdsp.Log.Infof("sending set volume command", zap.String("block", block), zap.Int("level", level))
-	cmd = []byte(fmt.Sprintf("#AUD-LVL 1,%s,%v\r", block, level))
-
-	//check to see if the mute status is going to be changing
-	currentVolume, err := vsdsp.Volumes(ctx, []string{block})
-	if err != nil {
-		vsdsp.Log.Errorf("error sending command: %s", err.Error())
-		return fmt.Errorf("error sending command: %w", err)
-	}
-	//if there is a change, two responses will be sent and both need to be read
-	readAgain := false
-	if currentVolume[block] != level {
-		readAgain = true
-	}
+	cmd := NewCommand("AUD-LVL", "1", block, strconv.Itoa(level))
 
-	resp, err := vsdsp.SendCommand(ctx, cmd, readAgain)
-	if err != nil {
+	if _, err := vsdsp.Do(ctx, cmd); err != nil {
 		vsdsp.Log.Errorf("error sending command: %s", err.Error())
 		return fmt.Errorf("error sending command: %w", err)
 	}
-
-	resps := string(resp)
-	if strings.Contains(resps, "ERR") {
-		return fmt.Errorf("an error occured: (command: %s) response: %s)", cmd, resps)
-	}
 	vsdsp.Log.Infof("successfully set volume", zap.String("block", block), zap.Int("level", level))
 
 	return nil